@@ -0,0 +1,1112 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: api/admin.proto
+
+package admin
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AdminUser struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username        string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Like            []string               `protobuf:"bytes,3,rep,name=like,proto3" json:"like,omitempty"`
+	Role            string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"` // user、support、admin
+	Disabled        bool                   `protobuf:"varint,5,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	DisabledAt      string                 `protobuf:"bytes,6,opt,name=disabled_at,json=disabledAt,proto3" json:"disabled_at,omitempty"` // 未禁用时为空
+	TotpEnabled     bool                   `protobuf:"varint,7,opt,name=totp_enabled,json=totpEnabled,proto3" json:"totp_enabled,omitempty"`
+	EmbeddingStatus string                 `protobuf:"bytes,8,opt,name=embedding_status,json=embeddingStatus,proto3" json:"embedding_status,omitempty"`
+	CreateAt        string                 `protobuf:"bytes,9,opt,name=create_at,json=createAt,proto3" json:"create_at,omitempty"`
+	UpdateAt        string                 `protobuf:"bytes,10,opt,name=update_at,json=updateAt,proto3" json:"update_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AdminUser) Reset() {
+	*x = AdminUser{}
+	mi := &file_api_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminUser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminUser) ProtoMessage() {}
+
+func (x *AdminUser) ProtoReflect() protoreflect.Message {
+	mi := &file_api_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminUser.ProtoReflect.Descriptor instead.
+func (*AdminUser) Descriptor() ([]byte, []int) {
+	return file_api_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AdminUser) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AdminUser) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *AdminUser) GetLike() []string {
+	if x != nil {
+		return x.Like
+	}
+	return nil
+}
+
+func (x *AdminUser) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *AdminUser) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *AdminUser) GetDisabledAt() string {
+	if x != nil {
+		return x.DisabledAt
+	}
+	return ""
+}
+
+func (x *AdminUser) GetTotpEnabled() bool {
+	if x != nil {
+		return x.TotpEnabled
+	}
+	return false
+}
+
+func (x *AdminUser) GetEmbeddingStatus() string {
+	if x != nil {
+		return x.EmbeddingStatus
+	}
+	return ""
+}
+
+func (x *AdminUser) GetCreateAt() string {
+	if x != nil {
+		return x.CreateAt
+	}
+	return ""
+}
+
+func (x *AdminUser) GetUpdateAt() string {
+	if x != nil {
+		return x.UpdateAt
+	}
+	return ""
+}
+
+type ListUsersReq struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	CreatedAfter   *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`    // 注册时间下限（包含），为空表示不限制
+	CreatedBefore  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"` // 注册时间上限（不包含），为空表示不限制
+	UsernamePrefix string                 `protobuf:"bytes,3,opt,name=username_prefix,json=usernamePrefix,proto3" json:"username_prefix,omitempty"`
+	LikeContains   string                 `protobuf:"bytes,4,opt,name=like_contains,json=likeContains,proto3" json:"like_contains,omitempty"` // 任意一个喜好包含该文本，不区分大小写
+	PageSize       int32                  `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`            // 每页数量，默认 10，最大 100
+	Cursor         string                 `protobuf:"bytes,6,opt,name=cursor,proto3" json:"cursor,omitempty"`                                 // 上一页返回的 next_cursor，为空表示第一页
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListUsersReq) Reset() {
+	*x = ListUsersReq{}
+	mi := &file_api_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersReq) ProtoMessage() {}
+
+func (x *ListUsersReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersReq.ProtoReflect.Descriptor instead.
+func (*ListUsersReq) Descriptor() ([]byte, []int) {
+	return file_api_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListUsersReq) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *ListUsersReq) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *ListUsersReq) GetUsernamePrefix() string {
+	if x != nil {
+		return x.UsernamePrefix
+	}
+	return ""
+}
+
+func (x *ListUsersReq) GetLikeContains() string {
+	if x != nil {
+		return x.LikeContains
+	}
+	return ""
+}
+
+func (x *ListUsersReq) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListUsersReq) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type ListUsersResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*AdminUser           `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // 为空表示没有更多结果
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersResp) Reset() {
+	*x = ListUsersResp{}
+	mi := &file_api_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersResp) ProtoMessage() {}
+
+func (x *ListUsersResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersResp.ProtoReflect.Descriptor instead.
+func (*ListUsersResp) Descriptor() ([]byte, []int) {
+	return file_api_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListUsersResp) GetUsers() []*AdminUser {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ListUsersResp) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type GetUserReq struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Identifier:
+	//
+	//	*GetUserReq_UserId
+	//	*GetUserReq_Username
+	Identifier    isGetUserReq_Identifier `protobuf_oneof:"identifier"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserReq) Reset() {
+	*x = GetUserReq{}
+	mi := &file_api_admin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserReq) ProtoMessage() {}
+
+func (x *GetUserReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_admin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserReq.ProtoReflect.Descriptor instead.
+func (*GetUserReq) Descriptor() ([]byte, []int) {
+	return file_api_admin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetUserReq) GetIdentifier() isGetUserReq_Identifier {
+	if x != nil {
+		return x.Identifier
+	}
+	return nil
+}
+
+func (x *GetUserReq) GetUserId() string {
+	if x != nil {
+		if x, ok := x.Identifier.(*GetUserReq_UserId); ok {
+			return x.UserId
+		}
+	}
+	return ""
+}
+
+func (x *GetUserReq) GetUsername() string {
+	if x != nil {
+		if x, ok := x.Identifier.(*GetUserReq_Username); ok {
+			return x.Username
+		}
+	}
+	return ""
+}
+
+type isGetUserReq_Identifier interface {
+	isGetUserReq_Identifier()
+}
+
+type GetUserReq_UserId struct {
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3,oneof"`
+}
+
+type GetUserReq_Username struct {
+	Username string `protobuf:"bytes,2,opt,name=username,proto3,oneof"`
+}
+
+func (*GetUserReq_UserId) isGetUserReq_Identifier() {}
+
+func (*GetUserReq_Username) isGetUserReq_Identifier() {}
+
+type DisableUserReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // 记录到审计日志
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableUserReq) Reset() {
+	*x = DisableUserReq{}
+	mi := &file_api_admin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableUserReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableUserReq) ProtoMessage() {}
+
+func (x *DisableUserReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_admin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableUserReq.ProtoReflect.Descriptor instead.
+func (*DisableUserReq) Descriptor() ([]byte, []int) {
+	return file_api_admin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DisableUserReq) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DisableUserReq) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type DisableUserResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableUserResp) Reset() {
+	*x = DisableUserResp{}
+	mi := &file_api_admin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableUserResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableUserResp) ProtoMessage() {}
+
+func (x *DisableUserResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_admin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableUserResp.ProtoReflect.Descriptor instead.
+func (*DisableUserResp) Descriptor() ([]byte, []int) {
+	return file_api_admin_proto_rawDescGZIP(), []int{5}
+}
+
+type EnableUserReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnableUserReq) Reset() {
+	*x = EnableUserReq{}
+	mi := &file_api_admin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnableUserReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnableUserReq) ProtoMessage() {}
+
+func (x *EnableUserReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_admin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnableUserReq.ProtoReflect.Descriptor instead.
+func (*EnableUserReq) Descriptor() ([]byte, []int) {
+	return file_api_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *EnableUserReq) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type EnableUserResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnableUserResp) Reset() {
+	*x = EnableUserResp{}
+	mi := &file_api_admin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnableUserResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnableUserResp) ProtoMessage() {}
+
+func (x *EnableUserResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_admin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnableUserResp.ProtoReflect.Descriptor instead.
+func (*EnableUserResp) Descriptor() ([]byte, []int) {
+	return file_api_admin_proto_rawDescGZIP(), []int{7}
+}
+
+type ForceLogoutReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForceLogoutReq) Reset() {
+	*x = ForceLogoutReq{}
+	mi := &file_api_admin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForceLogoutReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForceLogoutReq) ProtoMessage() {}
+
+func (x *ForceLogoutReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_admin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForceLogoutReq.ProtoReflect.Descriptor instead.
+func (*ForceLogoutReq) Descriptor() ([]byte, []int) {
+	return file_api_admin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ForceLogoutReq) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ForceLogoutResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForceLogoutResp) Reset() {
+	*x = ForceLogoutResp{}
+	mi := &file_api_admin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForceLogoutResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForceLogoutResp) ProtoMessage() {}
+
+func (x *ForceLogoutResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_admin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForceLogoutResp.ProtoReflect.Descriptor instead.
+func (*ForceLogoutResp) Descriptor() ([]byte, []int) {
+	return file_api_admin_proto_rawDescGZIP(), []int{9}
+}
+
+type ImportUsersReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`                             // 明文密码，与 password_hash 二选一
+	PasswordHash  string                 `protobuf:"bytes,3,opt,name=password_hash,json=passwordHash,proto3" json:"password_hash,omitempty"` // 从旧系统迁移的 bcrypt 哈希
+	Like          []string               `protobuf:"bytes,4,rep,name=like,proto3" json:"like,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportUsersReq) Reset() {
+	*x = ImportUsersReq{}
+	mi := &file_api_admin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportUsersReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportUsersReq) ProtoMessage() {}
+
+func (x *ImportUsersReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_admin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportUsersReq.ProtoReflect.Descriptor instead.
+func (*ImportUsersReq) Descriptor() ([]byte, []int) {
+	return file_api_admin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ImportUsersReq) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ImportUsersReq) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *ImportUsersReq) GetPasswordHash() string {
+	if x != nil {
+		return x.PasswordHash
+	}
+	return ""
+}
+
+func (x *ImportUsersReq) GetLike() []string {
+	if x != nil {
+		return x.Like
+	}
+	return nil
+}
+
+type ImportUserResult struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Row             int32                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"` // 第几条消息，从 1 开始
+	Username        string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	UserId          string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status          string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`                                          // created、exists、failed
+	EmbeddingStatus string                 `protobuf:"bytes,5,opt,name=embedding_status,json=embeddingStatus,proto3" json:"embedding_status,omitempty"` // 新建用户的词嵌入向量生成状态
+	Error           string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`                                            // 失败原因
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ImportUserResult) Reset() {
+	*x = ImportUserResult{}
+	mi := &file_api_admin_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportUserResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportUserResult) ProtoMessage() {}
+
+func (x *ImportUserResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_admin_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportUserResult.ProtoReflect.Descriptor instead.
+func (*ImportUserResult) Descriptor() ([]byte, []int) {
+	return file_api_admin_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ImportUserResult) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *ImportUserResult) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ImportUserResult) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ImportUserResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ImportUserResult) GetEmbeddingStatus() string {
+	if x != nil {
+		return x.EmbeddingStatus
+	}
+	return ""
+}
+
+func (x *ImportUserResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ImportUsersResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Created       int32                  `protobuf:"varint,1,opt,name=created,proto3" json:"created,omitempty"`   // 截至本批累计新建的用户数
+	Existing      int32                  `protobuf:"varint,2,opt,name=existing,proto3" json:"existing,omitempty"` // 截至本批累计已存在的用户数
+	Failed        int32                  `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`     // 截至本批累计失败的行数
+	Results       []*ImportUserResult    `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"`    // 本批每一行的结果，按消息顺序
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportUsersResp) Reset() {
+	*x = ImportUsersResp{}
+	mi := &file_api_admin_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportUsersResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportUsersResp) ProtoMessage() {}
+
+func (x *ImportUsersResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_admin_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportUsersResp.ProtoReflect.Descriptor instead.
+func (*ImportUsersResp) Descriptor() ([]byte, []int) {
+	return file_api_admin_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ImportUsersResp) GetCreated() int32 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *ImportUsersResp) GetExisting() int32 {
+	if x != nil {
+		return x.Existing
+	}
+	return 0
+}
+
+func (x *ImportUsersResp) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *ImportUsersResp) GetResults() []*ImportUserResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type ExportUsersReq struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	CreatedAfter        *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`    // 注册时间下限（包含），为空表示不限制
+	CreatedBefore       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"` // 注册时间上限（不包含），为空表示不限制
+	UsernamePrefix      string                 `protobuf:"bytes,3,opt,name=username_prefix,json=usernamePrefix,proto3" json:"username_prefix,omitempty"`
+	LikeContains        string                 `protobuf:"bytes,4,opt,name=like_contains,json=likeContains,proto3" json:"like_contains,omitempty"`
+	IncludePasswordHash bool                   `protobuf:"varint,5,opt,name=include_password_hash,json=includePasswordHash,proto3" json:"include_password_hash,omitempty"` // 导出密码哈希，用于迁移到其他环境
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ExportUsersReq) Reset() {
+	*x = ExportUsersReq{}
+	mi := &file_api_admin_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUsersReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUsersReq) ProtoMessage() {}
+
+func (x *ExportUsersReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_admin_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUsersReq.ProtoReflect.Descriptor instead.
+func (*ExportUsersReq) Descriptor() ([]byte, []int) {
+	return file_api_admin_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ExportUsersReq) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *ExportUsersReq) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *ExportUsersReq) GetUsernamePrefix() string {
+	if x != nil {
+		return x.UsernamePrefix
+	}
+	return ""
+}
+
+func (x *ExportUsersReq) GetLikeContains() string {
+	if x != nil {
+		return x.LikeContains
+	}
+	return ""
+}
+
+func (x *ExportUsersReq) GetIncludePasswordHash() bool {
+	if x != nil {
+		return x.IncludePasswordHash
+	}
+	return false
+}
+
+type ExportUsersResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Content       []byte                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"` // 按顺序拼接所有分块得到 JSONL 文件，每行一个用户，可以直接用于 ImportUsers
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportUsersResp) Reset() {
+	*x = ExportUsersResp{}
+	mi := &file_api_admin_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUsersResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUsersResp) ProtoMessage() {}
+
+func (x *ExportUsersResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_admin_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUsersResp.ProtoReflect.Descriptor instead.
+func (*ExportUsersResp) Descriptor() ([]byte, []int) {
+	return file_api_admin_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ExportUsersResp) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+var File_api_admin_proto protoreflect.FileDescriptor
+
+const file_api_admin_proto_rawDesc = "" +
+	"\n" +
+	"\x0fapi/admin.proto\x12\x05admin\x1a\x1fgoogle/protobuf/timestamp.proto\"\xad\x02\n" +
+	"\tAdminUser\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x12\n" +
+	"\x04like\x18\x03 \x03(\tR\x04like\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\x12\x1a\n" +
+	"\bdisabled\x18\x05 \x01(\bR\bdisabled\x12\x1f\n" +
+	"\vdisabled_at\x18\x06 \x01(\tR\n" +
+	"disabledAt\x12!\n" +
+	"\ftotp_enabled\x18\a \x01(\bR\vtotpEnabled\x12)\n" +
+	"\x10embedding_status\x18\b \x01(\tR\x0fembeddingStatus\x12\x1b\n" +
+	"\tcreate_at\x18\t \x01(\tR\bcreateAt\x12\x1b\n" +
+	"\tupdate_at\x18\n" +
+	" \x01(\tR\bupdateAt\"\x95\x02\n" +
+	"\fListUsersReq\x12?\n" +
+	"\rcreated_after\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12'\n" +
+	"\x0fusername_prefix\x18\x03 \x01(\tR\x0eusernamePrefix\x12#\n" +
+	"\rlike_contains\x18\x04 \x01(\tR\flikeContains\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\x12\x16\n" +
+	"\x06cursor\x18\x06 \x01(\tR\x06cursor\"X\n" +
+	"\rListUsersResp\x12&\n" +
+	"\x05users\x18\x01 \x03(\v2\x10.admin.AdminUserR\x05users\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"S\n" +
+	"\n" +
+	"GetUserReq\x12\x19\n" +
+	"\auser_id\x18\x01 \x01(\tH\x00R\x06userId\x12\x1c\n" +
+	"\busername\x18\x02 \x01(\tH\x00R\busernameB\f\n" +
+	"\n" +
+	"identifier\"A\n" +
+	"\x0eDisableUserReq\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\x11\n" +
+	"\x0fDisableUserResp\"(\n" +
+	"\rEnableUserReq\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\x10\n" +
+	"\x0eEnableUserResp\")\n" +
+	"\x0eForceLogoutReq\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\x11\n" +
+	"\x0fForceLogoutResp\"\x81\x01\n" +
+	"\x0eImportUsersReq\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\x12#\n" +
+	"\rpassword_hash\x18\x03 \x01(\tR\fpasswordHash\x12\x12\n" +
+	"\x04like\x18\x04 \x03(\tR\x04like\"\xb2\x01\n" +
+	"\x10ImportUserResult\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x05R\x03row\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12)\n" +
+	"\x10embedding_status\x18\x05 \x01(\tR\x0fembeddingStatus\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\"\x92\x01\n" +
+	"\x0fImportUsersResp\x12\x18\n" +
+	"\acreated\x18\x01 \x01(\x05R\acreated\x12\x1a\n" +
+	"\bexisting\x18\x02 \x01(\x05R\bexisting\x12\x16\n" +
+	"\x06failed\x18\x03 \x01(\x05R\x06failed\x121\n" +
+	"\aresults\x18\x04 \x03(\v2\x17.admin.ImportUserResultR\aresults\"\x96\x02\n" +
+	"\x0eExportUsersReq\x12?\n" +
+	"\rcreated_after\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12'\n" +
+	"\x0fusername_prefix\x18\x03 \x01(\tR\x0eusernamePrefix\x12#\n" +
+	"\rlike_contains\x18\x04 \x01(\tR\flikeContains\x122\n" +
+	"\x15include_password_hash\x18\x05 \x01(\bR\x13includePasswordHash\"+\n" +
+	"\x0fExportUsersResp\x12\x18\n" +
+	"\acontent\x18\x01 \x01(\fR\acontent2\xaf\x03\n" +
+	"\fAdminService\x126\n" +
+	"\tListUsers\x12\x13.admin.ListUsersReq\x1a\x14.admin.ListUsersResp\x12.\n" +
+	"\aGetUser\x12\x11.admin.GetUserReq\x1a\x10.admin.AdminUser\x12<\n" +
+	"\vDisableUser\x12\x15.admin.DisableUserReq\x1a\x16.admin.DisableUserResp\x129\n" +
+	"\n" +
+	"EnableUser\x12\x14.admin.EnableUserReq\x1a\x15.admin.EnableUserResp\x12<\n" +
+	"\vForceLogout\x12\x15.admin.ForceLogoutReq\x1a\x16.admin.ForceLogoutResp\x12@\n" +
+	"\vImportUsers\x12\x15.admin.ImportUsersReq\x1a\x16.admin.ImportUsersResp(\x010\x01\x12>\n" +
+	"\vExportUsers\x12\x15.admin.ExportUsersReq\x1a\x16.admin.ExportUsersResp0\x01B\bZ\x06/adminb\x06proto3"
+
+var (
+	file_api_admin_proto_rawDescOnce sync.Once
+	file_api_admin_proto_rawDescData []byte
+)
+
+func file_api_admin_proto_rawDescGZIP() []byte {
+	file_api_admin_proto_rawDescOnce.Do(func() {
+		file_api_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_admin_proto_rawDesc), len(file_api_admin_proto_rawDesc)))
+	})
+	return file_api_admin_proto_rawDescData
+}
+
+var file_api_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_api_admin_proto_goTypes = []any{
+	(*AdminUser)(nil),             // 0: admin.AdminUser
+	(*ListUsersReq)(nil),          // 1: admin.ListUsersReq
+	(*ListUsersResp)(nil),         // 2: admin.ListUsersResp
+	(*GetUserReq)(nil),            // 3: admin.GetUserReq
+	(*DisableUserReq)(nil),        // 4: admin.DisableUserReq
+	(*DisableUserResp)(nil),       // 5: admin.DisableUserResp
+	(*EnableUserReq)(nil),         // 6: admin.EnableUserReq
+	(*EnableUserResp)(nil),        // 7: admin.EnableUserResp
+	(*ForceLogoutReq)(nil),        // 8: admin.ForceLogoutReq
+	(*ForceLogoutResp)(nil),       // 9: admin.ForceLogoutResp
+	(*ImportUsersReq)(nil),        // 10: admin.ImportUsersReq
+	(*ImportUserResult)(nil),      // 11: admin.ImportUserResult
+	(*ImportUsersResp)(nil),       // 12: admin.ImportUsersResp
+	(*ExportUsersReq)(nil),        // 13: admin.ExportUsersReq
+	(*ExportUsersResp)(nil),       // 14: admin.ExportUsersResp
+	(*timestamppb.Timestamp)(nil), // 15: google.protobuf.Timestamp
+}
+var file_api_admin_proto_depIdxs = []int32{
+	15, // 0: admin.ListUsersReq.created_after:type_name -> google.protobuf.Timestamp
+	15, // 1: admin.ListUsersReq.created_before:type_name -> google.protobuf.Timestamp
+	0,  // 2: admin.ListUsersResp.users:type_name -> admin.AdminUser
+	11, // 3: admin.ImportUsersResp.results:type_name -> admin.ImportUserResult
+	15, // 4: admin.ExportUsersReq.created_after:type_name -> google.protobuf.Timestamp
+	15, // 5: admin.ExportUsersReq.created_before:type_name -> google.protobuf.Timestamp
+	1,  // 6: admin.AdminService.ListUsers:input_type -> admin.ListUsersReq
+	3,  // 7: admin.AdminService.GetUser:input_type -> admin.GetUserReq
+	4,  // 8: admin.AdminService.DisableUser:input_type -> admin.DisableUserReq
+	6,  // 9: admin.AdminService.EnableUser:input_type -> admin.EnableUserReq
+	8,  // 10: admin.AdminService.ForceLogout:input_type -> admin.ForceLogoutReq
+	10, // 11: admin.AdminService.ImportUsers:input_type -> admin.ImportUsersReq
+	13, // 12: admin.AdminService.ExportUsers:input_type -> admin.ExportUsersReq
+	2,  // 13: admin.AdminService.ListUsers:output_type -> admin.ListUsersResp
+	0,  // 14: admin.AdminService.GetUser:output_type -> admin.AdminUser
+	5,  // 15: admin.AdminService.DisableUser:output_type -> admin.DisableUserResp
+	7,  // 16: admin.AdminService.EnableUser:output_type -> admin.EnableUserResp
+	9,  // 17: admin.AdminService.ForceLogout:output_type -> admin.ForceLogoutResp
+	12, // 18: admin.AdminService.ImportUsers:output_type -> admin.ImportUsersResp
+	14, // 19: admin.AdminService.ExportUsers:output_type -> admin.ExportUsersResp
+	13, // [13:20] is the sub-list for method output_type
+	6,  // [6:13] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_api_admin_proto_init() }
+func file_api_admin_proto_init() {
+	if File_api_admin_proto != nil {
+		return
+	}
+	file_api_admin_proto_msgTypes[3].OneofWrappers = []any{
+		(*GetUserReq_UserId)(nil),
+		(*GetUserReq_Username)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_admin_proto_rawDesc), len(file_api_admin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_admin_proto_goTypes,
+		DependencyIndexes: file_api_admin_proto_depIdxs,
+		MessageInfos:      file_api_admin_proto_msgTypes,
+	}.Build()
+	File_api_admin_proto = out.File
+	file_api_admin_proto_goTypes = nil
+	file_api_admin_proto_depIdxs = nil
+}
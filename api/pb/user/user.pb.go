@@ -9,6 +9,7 @@ package user
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -127,8 +128,9 @@ func (x *RegisterResp) GetUserId() string {
 
 type LoginReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // user_id 和 username 二选一
 	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -177,11 +179,22 @@ func (x *LoginReq) GetPassword() string {
 	return ""
 }
 
+func (x *LoginReq) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
 type LoginResp struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken    string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	RefreshToken   string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`  // 用于换取新的 access_token，每次使用后轮换
+	ExpiresIn      int64                  `protobuf:"varint,3,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`          // access_token 有效期，单位秒
+	TotpRequired   bool                   `protobuf:"varint,4,opt,name=totp_required,json=totpRequired,proto3" json:"totp_required,omitempty"` // 为 true 时不返回 token，需要使用 challenge_token 调用 VerifyTOTP
+	ChallengeToken string                 `protobuf:"bytes,5,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *LoginResp) Reset() {
@@ -221,26 +234,56 @@ func (x *LoginResp) GetAccessToken() string {
 	return ""
 }
 
-type UserInfoReq struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *LoginResp) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
 }
 
-func (x *UserInfoReq) Reset() {
-	*x = UserInfoReq{}
+func (x *LoginResp) GetExpiresIn() int64 {
+	if x != nil {
+		return x.ExpiresIn
+	}
+	return 0
+}
+
+func (x *LoginResp) GetTotpRequired() bool {
+	if x != nil {
+		return x.TotpRequired
+	}
+	return false
+}
+
+func (x *LoginResp) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+type VerifyTOTPReq struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ChallengeToken string                 `protobuf:"bytes,1,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	Code           string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"` // 验证器 App 中的 6 位验证码，或一个未使用的恢复码
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *VerifyTOTPReq) Reset() {
+	*x = VerifyTOTPReq{}
 	mi := &file_api_user_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserInfoReq) String() string {
+func (x *VerifyTOTPReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserInfoReq) ProtoMessage() {}
+func (*VerifyTOTPReq) ProtoMessage() {}
 
-func (x *UserInfoReq) ProtoReflect() protoreflect.Message {
+func (x *VerifyTOTPReq) ProtoReflect() protoreflect.Message {
 	mi := &file_api_user_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -252,37 +295,45 @@ func (x *UserInfoReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserInfoReq.ProtoReflect.Descriptor instead.
-func (*UserInfoReq) Descriptor() ([]byte, []int) {
+// Deprecated: Use VerifyTOTPReq.ProtoReflect.Descriptor instead.
+func (*VerifyTOTPReq) Descriptor() ([]byte, []int) {
 	return file_api_user_proto_rawDescGZIP(), []int{4}
 }
 
-type UserInfoResp struct {
+func (x *VerifyTOTPReq) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+func (x *VerifyTOTPReq) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type EnrollTOTPReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Like          []string               `protobuf:"bytes,2,rep,name=like,proto3" json:"like,omitempty"`
-	LikeEmbedding []float32              `protobuf:"fixed32,3,rep,packed,name=like_embedding,json=likeEmbedding,proto3" json:"like_embedding,omitempty"`
-	CreateAt      string                 `protobuf:"bytes,4,opt,name=create_at,json=createAt,proto3" json:"create_at,omitempty"`
-	UpdateAt      string                 `protobuf:"bytes,5,opt,name=update_at,json=updateAt,proto3" json:"update_at,omitempty"`
-	Username      string                 `protobuf:"bytes,6,opt,name=username,proto3" json:"username,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UserInfoResp) Reset() {
-	*x = UserInfoResp{}
+func (x *EnrollTOTPReq) Reset() {
+	*x = EnrollTOTPReq{}
 	mi := &file_api_user_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserInfoResp) String() string {
+func (x *EnrollTOTPReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserInfoResp) ProtoMessage() {}
+func (*EnrollTOTPReq) ProtoMessage() {}
 
-func (x *UserInfoResp) ProtoReflect() protoreflect.Message {
+func (x *EnrollTOTPReq) ProtoReflect() protoreflect.Message {
 	mi := &file_api_user_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -294,81 +345,2101 @@ func (x *UserInfoResp) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserInfoResp.ProtoReflect.Descriptor instead.
-func (*UserInfoResp) Descriptor() ([]byte, []int) {
+// Deprecated: Use EnrollTOTPReq.ProtoReflect.Descriptor instead.
+func (*EnrollTOTPReq) Descriptor() ([]byte, []int) {
 	return file_api_user_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *UserInfoResp) GetUserId() string {
+type EnrollTOTPResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Secret        string                 `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`                           // base32 编码的密钥，可手动输入验证器 App
+	OtpauthUri    string                 `protobuf:"bytes,2,opt,name=otpauth_uri,json=otpauthUri,proto3" json:"otpauth_uri,omitempty"` // 可生成二维码供验证器 App 扫描
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnrollTOTPResp) Reset() {
+	*x = EnrollTOTPResp{}
+	mi := &file_api_user_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollTOTPResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollTOTPResp) ProtoMessage() {}
+
+func (x *EnrollTOTPResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[6]
 	if x != nil {
-		return x.UserId
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollTOTPResp.ProtoReflect.Descriptor instead.
+func (*EnrollTOTPResp) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *EnrollTOTPResp) GetSecret() string {
+	if x != nil {
+		return x.Secret
 	}
 	return ""
 }
 
-func (x *UserInfoResp) GetLike() []string {
+func (x *EnrollTOTPResp) GetOtpauthUri() string {
 	if x != nil {
-		return x.Like
+		return x.OtpauthUri
 	}
-	return nil
+	return ""
 }
 
-func (x *UserInfoResp) GetLikeEmbedding() []float32 {
+type ConfirmTOTPReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmTOTPReq) Reset() {
+	*x = ConfirmTOTPReq{}
+	mi := &file_api_user_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmTOTPReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmTOTPReq) ProtoMessage() {}
+
+func (x *ConfirmTOTPReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[7]
 	if x != nil {
-		return x.LikeEmbedding
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmTOTPReq.ProtoReflect.Descriptor instead.
+func (*ConfirmTOTPReq) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ConfirmTOTPReq) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type ConfirmTOTPResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RecoveryCodes []string               `protobuf:"bytes,1,rep,name=recovery_codes,json=recoveryCodes,proto3" json:"recovery_codes,omitempty"` // 只返回这一次，每个恢复码只能使用一次
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmTOTPResp) Reset() {
+	*x = ConfirmTOTPResp{}
+	mi := &file_api_user_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmTOTPResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmTOTPResp) ProtoMessage() {}
+
+func (x *ConfirmTOTPResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmTOTPResp.ProtoReflect.Descriptor instead.
+func (*ConfirmTOTPResp) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ConfirmTOTPResp) GetRecoveryCodes() []string {
+	if x != nil {
+		return x.RecoveryCodes
 	}
 	return nil
 }
 
-func (x *UserInfoResp) GetCreateAt() string {
+type RefreshTokenReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshTokenReq) Reset() {
+	*x = RefreshTokenReq{}
+	mi := &file_api_user_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshTokenReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshTokenReq) ProtoMessage() {}
+
+func (x *RefreshTokenReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[9]
 	if x != nil {
-		return x.CreateAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshTokenReq.ProtoReflect.Descriptor instead.
+func (*RefreshTokenReq) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RefreshTokenReq) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
 	}
 	return ""
 }
 
-func (x *UserInfoResp) GetUpdateAt() string {
+type RefreshTokenResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	RefreshToken  string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"` // 新的 refresh_token，原 refresh_token 失效
+	ExpiresIn     int64                  `protobuf:"varint,3,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`         // access_token 有效期，单位秒
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshTokenResp) Reset() {
+	*x = RefreshTokenResp{}
+	mi := &file_api_user_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshTokenResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshTokenResp) ProtoMessage() {}
+
+func (x *RefreshTokenResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[10]
 	if x != nil {
-		return x.UpdateAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshTokenResp.ProtoReflect.Descriptor instead.
+func (*RefreshTokenResp) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RefreshTokenResp) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
 	}
 	return ""
 }
 
-func (x *UserInfoResp) GetUsername() string {
+func (x *RefreshTokenResp) GetRefreshToken() string {
 	if x != nil {
-		return x.Username
+		return x.RefreshToken
 	}
 	return ""
 }
 
-var File_api_user_proto protoreflect.FileDescriptor
+func (x *RefreshTokenResp) GetExpiresIn() int64 {
+	if x != nil {
+		return x.ExpiresIn
+	}
+	return 0
+}
 
-const file_api_user_proto_rawDesc = "" +
-	"\n" +
-	"\x0eapi/user.proto\x12\x04user\"Y\n" +
-	"\vRegisterReq\x12\x1a\n" +
-	"\bpassword\x18\x01 \x01(\tR\bpassword\x12\x12\n" +
-	"\x04like\x18\x02 \x03(\tR\x04like\x12\x1a\n" +
-	"\busername\x18\x03 \x01(\tR\busername\"'\n" +
-	"\fRegisterResp\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\"?\n" +
-	"\bLoginReq\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\".\n" +
-	"\tLoginResp\x12!\n" +
-	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\"\r\n" +
-	"\vUserInfoReq\"\xb8\x01\n" +
-	"\fUserInfoResp\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
-	"\x04like\x18\x02 \x03(\tR\x04like\x12%\n" +
-	"\x0elike_embedding\x18\x03 \x03(\x02R\rlikeEmbedding\x12\x1b\n" +
-	"\tcreate_at\x18\x04 \x01(\tR\bcreateAt\x12\x1b\n" +
-	"\tupdate_at\x18\x05 \x01(\tR\bupdateAt\x12\x1a\n" +
-	"\busername\x18\x06 \x01(\tR\busername2\xa0\x01\n" +
-	"\vUserService\x121\n" +
-	"\bRegister\x12\x11.user.RegisterReq\x1a\x12.user.RegisterResp\x12(\n" +
-	"\x05Login\x12\x0e.user.LoginReq\x1a\x0f.user.LoginResp\x124\n" +
-	"\vGetUserInfo\x12\x11.user.UserInfoReq\x1a\x12.user.UserInfoRespB\aZ\x05/userb\x06proto3"
+type LogoutReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogoutReq) Reset() {
+	*x = LogoutReq{}
+	mi := &file_api_user_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogoutReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutReq) ProtoMessage() {}
+
+func (x *LogoutReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutReq.ProtoReflect.Descriptor instead.
+func (*LogoutReq) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{11}
+}
+
+type LogoutResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogoutResp) Reset() {
+	*x = LogoutResp{}
+	mi := &file_api_user_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogoutResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutResp) ProtoMessage() {}
+
+func (x *LogoutResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutResp.ProtoReflect.Descriptor instead.
+func (*LogoutResp) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{12}
+}
+
+type LogoutAllSessionsReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogoutAllSessionsReq) Reset() {
+	*x = LogoutAllSessionsReq{}
+	mi := &file_api_user_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogoutAllSessionsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutAllSessionsReq) ProtoMessage() {}
+
+func (x *LogoutAllSessionsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutAllSessionsReq.ProtoReflect.Descriptor instead.
+func (*LogoutAllSessionsReq) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{13}
+}
+
+type LogoutAllSessionsResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogoutAllSessionsResp) Reset() {
+	*x = LogoutAllSessionsResp{}
+	mi := &file_api_user_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogoutAllSessionsResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutAllSessionsResp) ProtoMessage() {}
+
+func (x *LogoutAllSessionsResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutAllSessionsResp.ProtoReflect.Descriptor instead.
+func (*LogoutAllSessionsResp) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{14}
+}
+
+type Session struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	UserAgent     string                 `protobuf:"bytes,2,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"` // 登录时客户端的 user-agent
+	Ip            string                 `protobuf:"bytes,3,opt,name=ip,proto3" json:"ip,omitempty"`                                // 登录时客户端的 IP
+	CreatedAt     string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LastSeenAt    string                 `protobuf:"bytes,5,opt,name=last_seen_at,json=lastSeenAt,proto3" json:"last_seen_at,omitempty"` // 最后一次使用该会话访问的时间
+	Current       bool                   `protobuf:"varint,6,opt,name=current,proto3" json:"current,omitempty"`                          // 是否为当前请求所在的会话
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_api_user_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Session) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *Session) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *Session) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *Session) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Session) GetLastSeenAt() string {
+	if x != nil {
+		return x.LastSeenAt
+	}
+	return ""
+}
+
+func (x *Session) GetCurrent() bool {
+	if x != nil {
+		return x.Current
+	}
+	return false
+}
+
+type ListSessionsReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsReq) Reset() {
+	*x = ListSessionsReq{}
+	mi := &file_api_user_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsReq) ProtoMessage() {}
+
+func (x *ListSessionsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsReq.ProtoReflect.Descriptor instead.
+func (*ListSessionsReq) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{16}
+}
+
+type ListSessionsResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*Session             `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"` // 按最后活跃时间倒序
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsResp) Reset() {
+	*x = ListSessionsResp{}
+	mi := &file_api_user_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsResp) ProtoMessage() {}
+
+func (x *ListSessionsResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsResp.ProtoReflect.Descriptor instead.
+func (*ListSessionsResp) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListSessionsResp) GetSessions() []*Session {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+type RevokeSessionReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSessionReq) Reset() {
+	*x = RevokeSessionReq{}
+	mi := &file_api_user_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSessionReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSessionReq) ProtoMessage() {}
+
+func (x *RevokeSessionReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSessionReq.ProtoReflect.Descriptor instead.
+func (*RevokeSessionReq) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RevokeSessionReq) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type RevokeSessionResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSessionResp) Reset() {
+	*x = RevokeSessionResp{}
+	mi := &file_api_user_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSessionResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSessionResp) ProtoMessage() {}
+
+func (x *RevokeSessionResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSessionResp.ProtoReflect.Descriptor instead.
+func (*RevokeSessionResp) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{19}
+}
+
+type DeleteAccountReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Password      string                 `protobuf:"bytes,1,opt,name=password,proto3" json:"password,omitempty"` // 需要再次输入密码确认
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAccountReq) Reset() {
+	*x = DeleteAccountReq{}
+	mi := &file_api_user_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAccountReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAccountReq) ProtoMessage() {}
+
+func (x *DeleteAccountReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAccountReq.ProtoReflect.Descriptor instead.
+func (*DeleteAccountReq) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DeleteAccountReq) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type DeleteAccountResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PurgeAt       string                 `protobuf:"bytes,1,opt,name=purge_at,json=purgeAt,proto3" json:"purge_at,omitempty"` // 超过该时间后账号将被彻底删除，无法恢复
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAccountResp) Reset() {
+	*x = DeleteAccountResp{}
+	mi := &file_api_user_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAccountResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAccountResp) ProtoMessage() {}
+
+func (x *DeleteAccountResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAccountResp.ProtoReflect.Descriptor instead.
+func (*DeleteAccountResp) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *DeleteAccountResp) GetPurgeAt() string {
+	if x != nil {
+		return x.PurgeAt
+	}
+	return ""
+}
+
+type RestoreAccountReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // user_id 和 username 二选一
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreAccountReq) Reset() {
+	*x = RestoreAccountReq{}
+	mi := &file_api_user_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreAccountReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreAccountReq) ProtoMessage() {}
+
+func (x *RestoreAccountReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreAccountReq.ProtoReflect.Descriptor instead.
+func (*RestoreAccountReq) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RestoreAccountReq) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RestoreAccountReq) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *RestoreAccountReq) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type RestoreAccountResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreAccountResp) Reset() {
+	*x = RestoreAccountResp{}
+	mi := &file_api_user_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreAccountResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreAccountResp) ProtoMessage() {}
+
+func (x *RestoreAccountResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreAccountResp.ProtoReflect.Descriptor instead.
+func (*RestoreAccountResp) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{23}
+}
+
+type ExportMyDataReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Zip           bool                   `protobuf:"varint,1,opt,name=zip,proto3" json:"zip,omitempty"` // 为 true 时返回包含 data.json 的 zip 压缩包，否则直接返回 JSON
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportMyDataReq) Reset() {
+	*x = ExportMyDataReq{}
+	mi := &file_api_user_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportMyDataReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportMyDataReq) ProtoMessage() {}
+
+func (x *ExportMyDataReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportMyDataReq.ProtoReflect.Descriptor instead.
+func (*ExportMyDataReq) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ExportMyDataReq) GetZip() bool {
+	if x != nil {
+		return x.Zip
+	}
+	return false
+}
+
+type ExportMyDataResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Content       []byte                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"` // 按顺序拼接所有分块得到完整的导出文件
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportMyDataResp) Reset() {
+	*x = ExportMyDataResp{}
+	mi := &file_api_user_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportMyDataResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportMyDataResp) ProtoMessage() {}
+
+func (x *ExportMyDataResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportMyDataResp.ProtoReflect.Descriptor instead.
+func (*ExportMyDataResp) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ExportMyDataResp) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+type UserInfoReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserInfoReq) Reset() {
+	*x = UserInfoReq{}
+	mi := &file_api_user_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserInfoReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserInfoReq) ProtoMessage() {}
+
+func (x *UserInfoReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserInfoReq.ProtoReflect.Descriptor instead.
+func (*UserInfoReq) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{26}
+}
+
+type UserInfoResp struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Like            []string               `protobuf:"bytes,2,rep,name=like,proto3" json:"like,omitempty"`
+	LikeEmbedding   []float32              `protobuf:"fixed32,3,rep,packed,name=like_embedding,json=likeEmbedding,proto3" json:"like_embedding,omitempty"`
+	CreateAt        string                 `protobuf:"bytes,4,opt,name=create_at,json=createAt,proto3" json:"create_at,omitempty"`
+	UpdateAt        string                 `protobuf:"bytes,5,opt,name=update_at,json=updateAt,proto3" json:"update_at,omitempty"`
+	Username        string                 `protobuf:"bytes,6,opt,name=username,proto3" json:"username,omitempty"`
+	EmbeddingStatus string                 `protobuf:"bytes,7,opt,name=embedding_status,json=embeddingStatus,proto3" json:"embedding_status,omitempty"` // 词嵌入向量生成状态：pending、ready、failed，ready 之后才能使用兴趣推荐
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UserInfoResp) Reset() {
+	*x = UserInfoResp{}
+	mi := &file_api_user_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserInfoResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserInfoResp) ProtoMessage() {}
+
+func (x *UserInfoResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserInfoResp.ProtoReflect.Descriptor instead.
+func (*UserInfoResp) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *UserInfoResp) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserInfoResp) GetLike() []string {
+	if x != nil {
+		return x.Like
+	}
+	return nil
+}
+
+func (x *UserInfoResp) GetLikeEmbedding() []float32 {
+	if x != nil {
+		return x.LikeEmbedding
+	}
+	return nil
+}
+
+func (x *UserInfoResp) GetCreateAt() string {
+	if x != nil {
+		return x.CreateAt
+	}
+	return ""
+}
+
+func (x *UserInfoResp) GetUpdateAt() string {
+	if x != nil {
+		return x.UpdateAt
+	}
+	return ""
+}
+
+func (x *UserInfoResp) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *UserInfoResp) GetEmbeddingStatus() string {
+	if x != nil {
+		return x.EmbeddingStatus
+	}
+	return ""
+}
+
+type UpdateProfileReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Like          []string               `protobuf:"bytes,2,rep,name=like,proto3" json:"like,omitempty"`
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"` // 需要修改的字段，可选 username、like
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProfileReq) Reset() {
+	*x = UpdateProfileReq{}
+	mi := &file_api_user_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProfileReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProfileReq) ProtoMessage() {}
+
+func (x *UpdateProfileReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProfileReq.ProtoReflect.Descriptor instead.
+func (*UpdateProfileReq) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *UpdateProfileReq) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *UpdateProfileReq) GetLike() []string {
+	if x != nil {
+		return x.Like
+	}
+	return nil
+}
+
+func (x *UpdateProfileReq) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type ChangePasswordReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OldPassword   string                 `protobuf:"bytes,1,opt,name=old_password,json=oldPassword,proto3" json:"old_password,omitempty"`
+	NewPassword   string                 `protobuf:"bytes,2,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePasswordReq) Reset() {
+	*x = ChangePasswordReq{}
+	mi := &file_api_user_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordReq) ProtoMessage() {}
+
+func (x *ChangePasswordReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordReq.ProtoReflect.Descriptor instead.
+func (*ChangePasswordReq) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ChangePasswordReq) GetOldPassword() string {
+	if x != nil {
+		return x.OldPassword
+	}
+	return ""
+}
+
+func (x *ChangePasswordReq) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+type ChangePasswordResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"` // 修改密码后原 token 失效，使用新 token 继续访问
+	RefreshToken  string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	ExpiresIn     int64                  `protobuf:"varint,3,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"` // access_token 有效期，单位秒
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePasswordResp) Reset() {
+	*x = ChangePasswordResp{}
+	mi := &file_api_user_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordResp) ProtoMessage() {}
+
+func (x *ChangePasswordResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordResp.ProtoReflect.Descriptor instead.
+func (*ChangePasswordResp) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ChangePasswordResp) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *ChangePasswordResp) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *ChangePasswordResp) GetExpiresIn() int64 {
+	if x != nil {
+		return x.ExpiresIn
+	}
+	return 0
+}
+
+type RequestPasswordResetReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestPasswordResetReq) Reset() {
+	*x = RequestPasswordResetReq{}
+	mi := &file_api_user_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestPasswordResetReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestPasswordResetReq) ProtoMessage() {}
+
+func (x *RequestPasswordResetReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestPasswordResetReq.ProtoReflect.Descriptor instead.
+func (*RequestPasswordResetReq) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *RequestPasswordResetReq) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type RequestPasswordResetResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestPasswordResetResp) Reset() {
+	*x = RequestPasswordResetResp{}
+	mi := &file_api_user_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestPasswordResetResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestPasswordResetResp) ProtoMessage() {}
+
+func (x *RequestPasswordResetResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestPasswordResetResp.ProtoReflect.Descriptor instead.
+func (*RequestPasswordResetResp) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{32}
+}
+
+type ConfirmPasswordResetReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"` // 重置凭证，只能使用一次
+	NewPassword   string                 `protobuf:"bytes,2,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmPasswordResetReq) Reset() {
+	*x = ConfirmPasswordResetReq{}
+	mi := &file_api_user_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmPasswordResetReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmPasswordResetReq) ProtoMessage() {}
+
+func (x *ConfirmPasswordResetReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmPasswordResetReq.ProtoReflect.Descriptor instead.
+func (*ConfirmPasswordResetReq) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ConfirmPasswordResetReq) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *ConfirmPasswordResetReq) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+type ConfirmPasswordResetResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmPasswordResetResp) Reset() {
+	*x = ConfirmPasswordResetResp{}
+	mi := &file_api_user_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmPasswordResetResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmPasswordResetResp) ProtoMessage() {}
+
+func (x *ConfirmPasswordResetResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmPasswordResetResp.ProtoReflect.Descriptor instead.
+func (*ConfirmPasswordResetResp) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{34}
+}
+
+type FindSimilarUsersReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`                                 // 返回数量，默认 10，最大 100
+	MaxDistance   float32                `protobuf:"fixed32,2,opt,name=max_distance,json=maxDistance,proto3" json:"max_distance,omitempty"` // 余弦距离阈值，大于该值的用户不返回，0 表示不限制
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindSimilarUsersReq) Reset() {
+	*x = FindSimilarUsersReq{}
+	mi := &file_api_user_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindSimilarUsersReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindSimilarUsersReq) ProtoMessage() {}
+
+func (x *FindSimilarUsersReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindSimilarUsersReq.ProtoReflect.Descriptor instead.
+func (*FindSimilarUsersReq) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *FindSimilarUsersReq) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *FindSimilarUsersReq) GetMaxDistance() float32 {
+	if x != nil {
+		return x.MaxDistance
+	}
+	return 0
+}
+
+type SimilarUser struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Like          []string               `protobuf:"bytes,3,rep,name=like,proto3" json:"like,omitempty"`
+	Distance      float32                `protobuf:"fixed32,4,opt,name=distance,proto3" json:"distance,omitempty"` // 余弦距离，越小越相似
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SimilarUser) Reset() {
+	*x = SimilarUser{}
+	mi := &file_api_user_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimilarUser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimilarUser) ProtoMessage() {}
+
+func (x *SimilarUser) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimilarUser.ProtoReflect.Descriptor instead.
+func (*SimilarUser) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *SimilarUser) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SimilarUser) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *SimilarUser) GetLike() []string {
+	if x != nil {
+		return x.Like
+	}
+	return nil
+}
+
+func (x *SimilarUser) GetDistance() float32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+type FindSimilarUsersResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*SimilarUser         `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindSimilarUsersResp) Reset() {
+	*x = FindSimilarUsersResp{}
+	mi := &file_api_user_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindSimilarUsersResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindSimilarUsersResp) ProtoMessage() {}
+
+func (x *FindSimilarUsersResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindSimilarUsersResp.ProtoReflect.Descriptor instead.
+func (*FindSimilarUsersResp) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *FindSimilarUsersResp) GetUsers() []*SimilarUser {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+type SearchUsersByInterestReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`                        // 兴趣描述，例如 "mountain biking"
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"` // 每页数量，默认 10，最大 100
+	Cursor        string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`                      // 上一页返回的 next_cursor，为空表示第一页
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchUsersByInterestReq) Reset() {
+	*x = SearchUsersByInterestReq{}
+	mi := &file_api_user_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchUsersByInterestReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchUsersByInterestReq) ProtoMessage() {}
+
+func (x *SearchUsersByInterestReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchUsersByInterestReq.ProtoReflect.Descriptor instead.
+func (*SearchUsersByInterestReq) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *SearchUsersByInterestReq) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchUsersByInterestReq) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SearchUsersByInterestReq) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type SearchUsersByInterestResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*SimilarUser         `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // 为空表示没有更多结果
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchUsersByInterestResp) Reset() {
+	*x = SearchUsersByInterestResp{}
+	mi := &file_api_user_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchUsersByInterestResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchUsersByInterestResp) ProtoMessage() {}
+
+func (x *SearchUsersByInterestResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchUsersByInterestResp.ProtoReflect.Descriptor instead.
+func (*SearchUsersByInterestResp) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *SearchUsersByInterestResp) GetUsers() []*SimilarUser {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *SearchUsersByInterestResp) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type InterestMatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	MyLike        string                 `protobuf:"bytes,3,opt,name=my_like,json=myLike,proto3" json:"my_like,omitempty"`                // 当前用户被匹配上的喜好，按文本查找时为空
+	MatchedLike   string                 `protobuf:"bytes,4,opt,name=matched_like,json=matchedLike,proto3" json:"matched_like,omitempty"` // 对方被匹配上的喜好
+	Distance      float32                `protobuf:"fixed32,5,opt,name=distance,proto3" json:"distance,omitempty"`                        // 两个喜好向量的余弦距离，越小越相似
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InterestMatch) Reset() {
+	*x = InterestMatch{}
+	mi := &file_api_user_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InterestMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InterestMatch) ProtoMessage() {}
+
+func (x *InterestMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InterestMatch.ProtoReflect.Descriptor instead.
+func (*InterestMatch) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *InterestMatch) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *InterestMatch) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *InterestMatch) GetMyLike() string {
+	if x != nil {
+		return x.MyLike
+	}
+	return ""
+}
+
+func (x *InterestMatch) GetMatchedLike() string {
+	if x != nil {
+		return x.MatchedLike
+	}
+	return ""
+}
+
+func (x *InterestMatch) GetDistance() float32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+type FindUsersBySharedInterestReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`                                 // 返回数量，默认 10，最大 100
+	MaxDistance   float32                `protobuf:"fixed32,2,opt,name=max_distance,json=maxDistance,proto3" json:"max_distance,omitempty"` // 余弦距离阈值，0 表示不限制
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindUsersBySharedInterestReq) Reset() {
+	*x = FindUsersBySharedInterestReq{}
+	mi := &file_api_user_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindUsersBySharedInterestReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindUsersBySharedInterestReq) ProtoMessage() {}
+
+func (x *FindUsersBySharedInterestReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindUsersBySharedInterestReq.ProtoReflect.Descriptor instead.
+func (*FindUsersBySharedInterestReq) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *FindUsersBySharedInterestReq) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *FindUsersBySharedInterestReq) GetMaxDistance() float32 {
+	if x != nil {
+		return x.MaxDistance
+	}
+	return 0
+}
+
+type FindUsersBySharedInterestResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Matches       []*InterestMatch       `protobuf:"bytes,1,rep,name=matches,proto3" json:"matches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindUsersBySharedInterestResp) Reset() {
+	*x = FindUsersBySharedInterestResp{}
+	mi := &file_api_user_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindUsersBySharedInterestResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindUsersBySharedInterestResp) ProtoMessage() {}
+
+func (x *FindUsersBySharedInterestResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindUsersBySharedInterestResp.ProtoReflect.Descriptor instead.
+func (*FindUsersBySharedInterestResp) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *FindUsersBySharedInterestResp) GetMatches() []*InterestMatch {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+type FindUsersByLikeReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Like          string                 `protobuf:"bytes,1,opt,name=like,proto3" json:"like,omitempty"`                                    // 喜好文本，例如 "mountain biking"
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`                                 // 返回数量，默认 10，最大 100
+	MaxDistance   float32                `protobuf:"fixed32,3,opt,name=max_distance,json=maxDistance,proto3" json:"max_distance,omitempty"` // 余弦距离阈值，0 表示不限制
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindUsersByLikeReq) Reset() {
+	*x = FindUsersByLikeReq{}
+	mi := &file_api_user_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindUsersByLikeReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindUsersByLikeReq) ProtoMessage() {}
+
+func (x *FindUsersByLikeReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindUsersByLikeReq.ProtoReflect.Descriptor instead.
+func (*FindUsersByLikeReq) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *FindUsersByLikeReq) GetLike() string {
+	if x != nil {
+		return x.Like
+	}
+	return ""
+}
+
+func (x *FindUsersByLikeReq) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *FindUsersByLikeReq) GetMaxDistance() float32 {
+	if x != nil {
+		return x.MaxDistance
+	}
+	return 0
+}
+
+type FindUsersByLikeResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Matches       []*InterestMatch       `protobuf:"bytes,1,rep,name=matches,proto3" json:"matches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindUsersByLikeResp) Reset() {
+	*x = FindUsersByLikeResp{}
+	mi := &file_api_user_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindUsersByLikeResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindUsersByLikeResp) ProtoMessage() {}
+
+func (x *FindUsersByLikeResp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindUsersByLikeResp.ProtoReflect.Descriptor instead.
+func (*FindUsersByLikeResp) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *FindUsersByLikeResp) GetMatches() []*InterestMatch {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+var File_api_user_proto protoreflect.FileDescriptor
+
+const file_api_user_proto_rawDesc = "" +
+	"\n" +
+	"\x0eapi/user.proto\x12\x04user\x1a google/protobuf/field_mask.proto\"Y\n" +
+	"\vRegisterReq\x12\x1a\n" +
+	"\bpassword\x18\x01 \x01(\tR\bpassword\x12\x12\n" +
+	"\x04like\x18\x02 \x03(\tR\x04like\x12\x1a\n" +
+	"\busername\x18\x03 \x01(\tR\busername\"'\n" +
+	"\fRegisterResp\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"[\n" +
+	"\bLoginReq\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\x12\x1a\n" +
+	"\busername\x18\x03 \x01(\tR\busername\"\xc0\x01\n" +
+	"\tLoginResp\x12!\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\x12\x1d\n" +
+	"\n" +
+	"expires_in\x18\x03 \x01(\x03R\texpiresIn\x12#\n" +
+	"\rtotp_required\x18\x04 \x01(\bR\ftotpRequired\x12'\n" +
+	"\x0fchallenge_token\x18\x05 \x01(\tR\x0echallengeToken\"L\n" +
+	"\rVerifyTOTPReq\x12'\n" +
+	"\x0fchallenge_token\x18\x01 \x01(\tR\x0echallengeToken\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\"\x0f\n" +
+	"\rEnrollTOTPReq\"I\n" +
+	"\x0eEnrollTOTPResp\x12\x16\n" +
+	"\x06secret\x18\x01 \x01(\tR\x06secret\x12\x1f\n" +
+	"\votpauth_uri\x18\x02 \x01(\tR\n" +
+	"otpauthUri\"$\n" +
+	"\x0eConfirmTOTPReq\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"8\n" +
+	"\x0fConfirmTOTPResp\x12%\n" +
+	"\x0erecovery_codes\x18\x01 \x03(\tR\rrecoveryCodes\"6\n" +
+	"\x0fRefreshTokenReq\x12#\n" +
+	"\rrefresh_token\x18\x01 \x01(\tR\frefreshToken\"y\n" +
+	"\x10RefreshTokenResp\x12!\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\x12\x1d\n" +
+	"\n" +
+	"expires_in\x18\x03 \x01(\x03R\texpiresIn\"\v\n" +
+	"\tLogoutReq\"\f\n" +
+	"\n" +
+	"LogoutResp\"\x16\n" +
+	"\x14LogoutAllSessionsReq\"\x17\n" +
+	"\x15LogoutAllSessionsResp\"\xb2\x01\n" +
+	"\aSession\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x02 \x01(\tR\tuserAgent\x12\x0e\n" +
+	"\x02ip\x18\x03 \x01(\tR\x02ip\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12 \n" +
+	"\flast_seen_at\x18\x05 \x01(\tR\n" +
+	"lastSeenAt\x12\x18\n" +
+	"\acurrent\x18\x06 \x01(\bR\acurrent\"\x11\n" +
+	"\x0fListSessionsReq\"=\n" +
+	"\x10ListSessionsResp\x12)\n" +
+	"\bsessions\x18\x01 \x03(\v2\r.user.SessionR\bsessions\"1\n" +
+	"\x10RevokeSessionReq\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"\x13\n" +
+	"\x11RevokeSessionResp\".\n" +
+	"\x10DeleteAccountReq\x12\x1a\n" +
+	"\bpassword\x18\x01 \x01(\tR\bpassword\".\n" +
+	"\x11DeleteAccountResp\x12\x19\n" +
+	"\bpurge_at\x18\x01 \x01(\tR\apurgeAt\"d\n" +
+	"\x11RestoreAccountReq\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\"\x14\n" +
+	"\x12RestoreAccountResp\"#\n" +
+	"\x0fExportMyDataReq\x12\x10\n" +
+	"\x03zip\x18\x01 \x01(\bR\x03zip\",\n" +
+	"\x10ExportMyDataResp\x12\x18\n" +
+	"\acontent\x18\x01 \x01(\fR\acontent\"\r\n" +
+	"\vUserInfoReq\"\xe3\x01\n" +
+	"\fUserInfoResp\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04like\x18\x02 \x03(\tR\x04like\x12%\n" +
+	"\x0elike_embedding\x18\x03 \x03(\x02R\rlikeEmbedding\x12\x1b\n" +
+	"\tcreate_at\x18\x04 \x01(\tR\bcreateAt\x12\x1b\n" +
+	"\tupdate_at\x18\x05 \x01(\tR\bupdateAt\x12\x1a\n" +
+	"\busername\x18\x06 \x01(\tR\busername\x12)\n" +
+	"\x10embedding_status\x18\a \x01(\tR\x0fembeddingStatus\"\x7f\n" +
+	"\x10UpdateProfileReq\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04like\x18\x02 \x03(\tR\x04like\x12;\n" +
+	"\vupdate_mask\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"Y\n" +
+	"\x11ChangePasswordReq\x12!\n" +
+	"\fold_password\x18\x01 \x01(\tR\voldPassword\x12!\n" +
+	"\fnew_password\x18\x02 \x01(\tR\vnewPassword\"{\n" +
+	"\x12ChangePasswordResp\x12!\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\x12\x1d\n" +
+	"\n" +
+	"expires_in\x18\x03 \x01(\x03R\texpiresIn\"5\n" +
+	"\x17RequestPasswordResetReq\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\"\x1a\n" +
+	"\x18RequestPasswordResetResp\"R\n" +
+	"\x17ConfirmPasswordResetReq\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12!\n" +
+	"\fnew_password\x18\x02 \x01(\tR\vnewPassword\"\x1a\n" +
+	"\x18ConfirmPasswordResetResp\"N\n" +
+	"\x13FindSimilarUsersReq\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12!\n" +
+	"\fmax_distance\x18\x02 \x01(\x02R\vmaxDistance\"r\n" +
+	"\vSimilarUser\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x12\n" +
+	"\x04like\x18\x03 \x03(\tR\x04like\x12\x1a\n" +
+	"\bdistance\x18\x04 \x01(\x02R\bdistance\"?\n" +
+	"\x14FindSimilarUsersResp\x12'\n" +
+	"\x05users\x18\x01 \x03(\v2\x11.user.SimilarUserR\x05users\"e\n" +
+	"\x18SearchUsersByInterestReq\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"e\n" +
+	"\x19SearchUsersByInterestResp\x12'\n" +
+	"\x05users\x18\x01 \x03(\v2\x11.user.SimilarUserR\x05users\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"\x9c\x01\n" +
+	"\rInterestMatch\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x17\n" +
+	"\amy_like\x18\x03 \x01(\tR\x06myLike\x12!\n" +
+	"\fmatched_like\x18\x04 \x01(\tR\vmatchedLike\x12\x1a\n" +
+	"\bdistance\x18\x05 \x01(\x02R\bdistance\"W\n" +
+	"\x1cFindUsersBySharedInterestReq\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12!\n" +
+	"\fmax_distance\x18\x02 \x01(\x02R\vmaxDistance\"N\n" +
+	"\x1dFindUsersBySharedInterestResp\x12-\n" +
+	"\amatches\x18\x01 \x03(\v2\x13.user.InterestMatchR\amatches\"a\n" +
+	"\x12FindUsersByLikeReq\x12\x12\n" +
+	"\x04like\x18\x01 \x01(\tR\x04like\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12!\n" +
+	"\fmax_distance\x18\x03 \x01(\x02R\vmaxDistance\"D\n" +
+	"\x13FindUsersByLikeResp\x12-\n" +
+	"\amatches\x18\x01 \x03(\v2\x13.user.InterestMatchR\amatches2\xcf\v\n" +
+	"\vUserService\x121\n" +
+	"\bRegister\x12\x11.user.RegisterReq\x1a\x12.user.RegisterResp\x12(\n" +
+	"\x05Login\x12\x0e.user.LoginReq\x1a\x0f.user.LoginResp\x122\n" +
+	"\n" +
+	"VerifyTOTP\x12\x13.user.VerifyTOTPReq\x1a\x0f.user.LoginResp\x12=\n" +
+	"\fRefreshToken\x12\x15.user.RefreshTokenReq\x1a\x16.user.RefreshTokenResp\x127\n" +
+	"\n" +
+	"EnrollTOTP\x12\x13.user.EnrollTOTPReq\x1a\x14.user.EnrollTOTPResp\x12:\n" +
+	"\vConfirmTOTP\x12\x14.user.ConfirmTOTPReq\x1a\x15.user.ConfirmTOTPResp\x12+\n" +
+	"\x06Logout\x12\x0f.user.LogoutReq\x1a\x10.user.LogoutResp\x12L\n" +
+	"\x11LogoutAllSessions\x12\x1a.user.LogoutAllSessionsReq\x1a\x1b.user.LogoutAllSessionsResp\x12=\n" +
+	"\fListSessions\x12\x15.user.ListSessionsReq\x1a\x16.user.ListSessionsResp\x12@\n" +
+	"\rRevokeSession\x12\x16.user.RevokeSessionReq\x1a\x17.user.RevokeSessionResp\x12@\n" +
+	"\rDeleteAccount\x12\x16.user.DeleteAccountReq\x1a\x17.user.DeleteAccountResp\x12C\n" +
+	"\x0eRestoreAccount\x12\x17.user.RestoreAccountReq\x1a\x18.user.RestoreAccountResp\x12?\n" +
+	"\fExportMyData\x12\x15.user.ExportMyDataReq\x1a\x16.user.ExportMyDataResp0\x01\x124\n" +
+	"\vGetUserInfo\x12\x11.user.UserInfoReq\x1a\x12.user.UserInfoResp\x12;\n" +
+	"\rUpdateProfile\x12\x16.user.UpdateProfileReq\x1a\x12.user.UserInfoResp\x12C\n" +
+	"\x0eChangePassword\x12\x17.user.ChangePasswordReq\x1a\x18.user.ChangePasswordResp\x12U\n" +
+	"\x14RequestPasswordReset\x12\x1d.user.RequestPasswordResetReq\x1a\x1e.user.RequestPasswordResetResp\x12U\n" +
+	"\x14ConfirmPasswordReset\x12\x1d.user.ConfirmPasswordResetReq\x1a\x1e.user.ConfirmPasswordResetResp\x12I\n" +
+	"\x10FindSimilarUsers\x12\x19.user.FindSimilarUsersReq\x1a\x1a.user.FindSimilarUsersResp\x12X\n" +
+	"\x15SearchUsersByInterest\x12\x1e.user.SearchUsersByInterestReq\x1a\x1f.user.SearchUsersByInterestResp\x12d\n" +
+	"\x19FindUsersBySharedInterest\x12\".user.FindUsersBySharedInterestReq\x1a#.user.FindUsersBySharedInterestResp\x12F\n" +
+	"\x0fFindUsersByLike\x12\x18.user.FindUsersByLikeReq\x1a\x19.user.FindUsersByLikeRespB\aZ\x05/userb\x06proto3"
 
 var (
 	file_api_user_proto_rawDescOnce sync.Once
@@ -382,27 +2453,111 @@ func file_api_user_proto_rawDescGZIP() []byte {
 	return file_api_user_proto_rawDescData
 }
 
-var file_api_user_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_api_user_proto_msgTypes = make([]protoimpl.MessageInfo, 45)
 var file_api_user_proto_goTypes = []any{
-	(*RegisterReq)(nil),  // 0: user.RegisterReq
-	(*RegisterResp)(nil), // 1: user.RegisterResp
-	(*LoginReq)(nil),     // 2: user.LoginReq
-	(*LoginResp)(nil),    // 3: user.LoginResp
-	(*UserInfoReq)(nil),  // 4: user.UserInfoReq
-	(*UserInfoResp)(nil), // 5: user.UserInfoResp
+	(*RegisterReq)(nil),                   // 0: user.RegisterReq
+	(*RegisterResp)(nil),                  // 1: user.RegisterResp
+	(*LoginReq)(nil),                      // 2: user.LoginReq
+	(*LoginResp)(nil),                     // 3: user.LoginResp
+	(*VerifyTOTPReq)(nil),                 // 4: user.VerifyTOTPReq
+	(*EnrollTOTPReq)(nil),                 // 5: user.EnrollTOTPReq
+	(*EnrollTOTPResp)(nil),                // 6: user.EnrollTOTPResp
+	(*ConfirmTOTPReq)(nil),                // 7: user.ConfirmTOTPReq
+	(*ConfirmTOTPResp)(nil),               // 8: user.ConfirmTOTPResp
+	(*RefreshTokenReq)(nil),               // 9: user.RefreshTokenReq
+	(*RefreshTokenResp)(nil),              // 10: user.RefreshTokenResp
+	(*LogoutReq)(nil),                     // 11: user.LogoutReq
+	(*LogoutResp)(nil),                    // 12: user.LogoutResp
+	(*LogoutAllSessionsReq)(nil),          // 13: user.LogoutAllSessionsReq
+	(*LogoutAllSessionsResp)(nil),         // 14: user.LogoutAllSessionsResp
+	(*Session)(nil),                       // 15: user.Session
+	(*ListSessionsReq)(nil),               // 16: user.ListSessionsReq
+	(*ListSessionsResp)(nil),              // 17: user.ListSessionsResp
+	(*RevokeSessionReq)(nil),              // 18: user.RevokeSessionReq
+	(*RevokeSessionResp)(nil),             // 19: user.RevokeSessionResp
+	(*DeleteAccountReq)(nil),              // 20: user.DeleteAccountReq
+	(*DeleteAccountResp)(nil),             // 21: user.DeleteAccountResp
+	(*RestoreAccountReq)(nil),             // 22: user.RestoreAccountReq
+	(*RestoreAccountResp)(nil),            // 23: user.RestoreAccountResp
+	(*ExportMyDataReq)(nil),               // 24: user.ExportMyDataReq
+	(*ExportMyDataResp)(nil),              // 25: user.ExportMyDataResp
+	(*UserInfoReq)(nil),                   // 26: user.UserInfoReq
+	(*UserInfoResp)(nil),                  // 27: user.UserInfoResp
+	(*UpdateProfileReq)(nil),              // 28: user.UpdateProfileReq
+	(*ChangePasswordReq)(nil),             // 29: user.ChangePasswordReq
+	(*ChangePasswordResp)(nil),            // 30: user.ChangePasswordResp
+	(*RequestPasswordResetReq)(nil),       // 31: user.RequestPasswordResetReq
+	(*RequestPasswordResetResp)(nil),      // 32: user.RequestPasswordResetResp
+	(*ConfirmPasswordResetReq)(nil),       // 33: user.ConfirmPasswordResetReq
+	(*ConfirmPasswordResetResp)(nil),      // 34: user.ConfirmPasswordResetResp
+	(*FindSimilarUsersReq)(nil),           // 35: user.FindSimilarUsersReq
+	(*SimilarUser)(nil),                   // 36: user.SimilarUser
+	(*FindSimilarUsersResp)(nil),          // 37: user.FindSimilarUsersResp
+	(*SearchUsersByInterestReq)(nil),      // 38: user.SearchUsersByInterestReq
+	(*SearchUsersByInterestResp)(nil),     // 39: user.SearchUsersByInterestResp
+	(*InterestMatch)(nil),                 // 40: user.InterestMatch
+	(*FindUsersBySharedInterestReq)(nil),  // 41: user.FindUsersBySharedInterestReq
+	(*FindUsersBySharedInterestResp)(nil), // 42: user.FindUsersBySharedInterestResp
+	(*FindUsersByLikeReq)(nil),            // 43: user.FindUsersByLikeReq
+	(*FindUsersByLikeResp)(nil),           // 44: user.FindUsersByLikeResp
+	(*fieldmaskpb.FieldMask)(nil),         // 45: google.protobuf.FieldMask
 }
 var file_api_user_proto_depIdxs = []int32{
-	0, // 0: user.UserService.Register:input_type -> user.RegisterReq
-	2, // 1: user.UserService.Login:input_type -> user.LoginReq
-	4, // 2: user.UserService.GetUserInfo:input_type -> user.UserInfoReq
-	1, // 3: user.UserService.Register:output_type -> user.RegisterResp
-	3, // 4: user.UserService.Login:output_type -> user.LoginResp
-	5, // 5: user.UserService.GetUserInfo:output_type -> user.UserInfoResp
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	15, // 0: user.ListSessionsResp.sessions:type_name -> user.Session
+	45, // 1: user.UpdateProfileReq.update_mask:type_name -> google.protobuf.FieldMask
+	36, // 2: user.FindSimilarUsersResp.users:type_name -> user.SimilarUser
+	36, // 3: user.SearchUsersByInterestResp.users:type_name -> user.SimilarUser
+	40, // 4: user.FindUsersBySharedInterestResp.matches:type_name -> user.InterestMatch
+	40, // 5: user.FindUsersByLikeResp.matches:type_name -> user.InterestMatch
+	0,  // 6: user.UserService.Register:input_type -> user.RegisterReq
+	2,  // 7: user.UserService.Login:input_type -> user.LoginReq
+	4,  // 8: user.UserService.VerifyTOTP:input_type -> user.VerifyTOTPReq
+	9,  // 9: user.UserService.RefreshToken:input_type -> user.RefreshTokenReq
+	5,  // 10: user.UserService.EnrollTOTP:input_type -> user.EnrollTOTPReq
+	7,  // 11: user.UserService.ConfirmTOTP:input_type -> user.ConfirmTOTPReq
+	11, // 12: user.UserService.Logout:input_type -> user.LogoutReq
+	13, // 13: user.UserService.LogoutAllSessions:input_type -> user.LogoutAllSessionsReq
+	16, // 14: user.UserService.ListSessions:input_type -> user.ListSessionsReq
+	18, // 15: user.UserService.RevokeSession:input_type -> user.RevokeSessionReq
+	20, // 16: user.UserService.DeleteAccount:input_type -> user.DeleteAccountReq
+	22, // 17: user.UserService.RestoreAccount:input_type -> user.RestoreAccountReq
+	24, // 18: user.UserService.ExportMyData:input_type -> user.ExportMyDataReq
+	26, // 19: user.UserService.GetUserInfo:input_type -> user.UserInfoReq
+	28, // 20: user.UserService.UpdateProfile:input_type -> user.UpdateProfileReq
+	29, // 21: user.UserService.ChangePassword:input_type -> user.ChangePasswordReq
+	31, // 22: user.UserService.RequestPasswordReset:input_type -> user.RequestPasswordResetReq
+	33, // 23: user.UserService.ConfirmPasswordReset:input_type -> user.ConfirmPasswordResetReq
+	35, // 24: user.UserService.FindSimilarUsers:input_type -> user.FindSimilarUsersReq
+	38, // 25: user.UserService.SearchUsersByInterest:input_type -> user.SearchUsersByInterestReq
+	41, // 26: user.UserService.FindUsersBySharedInterest:input_type -> user.FindUsersBySharedInterestReq
+	43, // 27: user.UserService.FindUsersByLike:input_type -> user.FindUsersByLikeReq
+	1,  // 28: user.UserService.Register:output_type -> user.RegisterResp
+	3,  // 29: user.UserService.Login:output_type -> user.LoginResp
+	3,  // 30: user.UserService.VerifyTOTP:output_type -> user.LoginResp
+	10, // 31: user.UserService.RefreshToken:output_type -> user.RefreshTokenResp
+	6,  // 32: user.UserService.EnrollTOTP:output_type -> user.EnrollTOTPResp
+	8,  // 33: user.UserService.ConfirmTOTP:output_type -> user.ConfirmTOTPResp
+	12, // 34: user.UserService.Logout:output_type -> user.LogoutResp
+	14, // 35: user.UserService.LogoutAllSessions:output_type -> user.LogoutAllSessionsResp
+	17, // 36: user.UserService.ListSessions:output_type -> user.ListSessionsResp
+	19, // 37: user.UserService.RevokeSession:output_type -> user.RevokeSessionResp
+	21, // 38: user.UserService.DeleteAccount:output_type -> user.DeleteAccountResp
+	23, // 39: user.UserService.RestoreAccount:output_type -> user.RestoreAccountResp
+	25, // 40: user.UserService.ExportMyData:output_type -> user.ExportMyDataResp
+	27, // 41: user.UserService.GetUserInfo:output_type -> user.UserInfoResp
+	27, // 42: user.UserService.UpdateProfile:output_type -> user.UserInfoResp
+	30, // 43: user.UserService.ChangePassword:output_type -> user.ChangePasswordResp
+	32, // 44: user.UserService.RequestPasswordReset:output_type -> user.RequestPasswordResetResp
+	34, // 45: user.UserService.ConfirmPasswordReset:output_type -> user.ConfirmPasswordResetResp
+	37, // 46: user.UserService.FindSimilarUsers:output_type -> user.FindSimilarUsersResp
+	39, // 47: user.UserService.SearchUsersByInterest:output_type -> user.SearchUsersByInterestResp
+	42, // 48: user.UserService.FindUsersBySharedInterest:output_type -> user.FindUsersBySharedInterestResp
+	44, // 49: user.UserService.FindUsersByLike:output_type -> user.FindUsersByLikeResp
+	28, // [28:50] is the sub-list for method output_type
+	6,  // [6:28] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_api_user_proto_init() }
@@ -416,7 +2571,7 @@ func file_api_user_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_user_proto_rawDesc), len(file_api_user_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   45,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -9,12 +9,17 @@ import (
 	"net/http"
 	_ "net/http/pprof"
 
+	"github.com/HCH1212/taxin/api/pb/admin"
 	"github.com/HCH1212/taxin/api/pb/system"
 	"github.com/HCH1212/taxin/api/pb/user"
+	"github.com/HCH1212/taxin/config"
 	"github.com/HCH1212/taxin/internal/dao"
 	"github.com/HCH1212/taxin/internal/middleware"
+	"github.com/HCH1212/taxin/internal/notify"
 	"github.com/HCH1212/taxin/internal/service"
 	"github.com/HCH1212/taxin/internal/tracing"
+	"github.com/HCH1212/taxin/internal/utils"
+	"github.com/HCH1212/taxin/internal/worker"
 	"github.com/joho/godotenv"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/otel"
@@ -27,16 +32,20 @@ import (
 func main() {
 	_ = godotenv.Load()
 
-	// 启动 pprof 服务
+	// 启动 pprof 和运行指标服务，只监听本机
 	go func() {
-		log.Println(http.ListenAndServe("localhost:6060", nil))
+		log.Println(http.ListenAndServe(httpAddress(), nil))
 	}()
 
 	app := fx.New(
-		// 初始化数据库和 Redis
+		// 初始化数据库、Redis、token、两步验证密钥的加密密钥、词嵌入服务和通知
 		fx.Invoke(func() {
 			dao.InitDB()
 			dao.InitRedis()
+			utils.InitJWT()
+			utils.InitTOTP()
+			utils.InitEmbedder()
+			notify.InitNotifier()
 		}),
 		// 提供 Jaeger 追踪器
 		fx.Provide(
@@ -47,8 +56,18 @@ func main() {
 			newListener,
 			newGRPCServer,
 		),
-		// 触发服务器启动
-		fx.Invoke(func(grpc *grpc.Server, tp func(context.Context) error) {}), // 添加对 tp 的依赖
+		// 提供 JWKS 服务，在 InitJWT 加载密钥之后启动
+		fx.Provide(
+			newJWKSServer,
+		),
+		// 提供异步词嵌入和清理已注销账号的后台任务
+		fx.Provide(
+			newEmbeddingWorker,
+			newPurgeWorker,
+		),
+		// 触发服务器和后台任务启动
+		fx.Invoke(func(grpc *grpc.Server, jwks *http.Server, tp func(context.Context) error, w *worker.EmbeddingWorker, p *worker.PurgeWorker) {
+		}), // 添加对 tp 的依赖
 		// 禁用日志
 		fx.NopLogger,
 	)
@@ -56,6 +75,57 @@ func main() {
 	app.Run()
 }
 
+// 旁路 HTTP 服务的监听地址
+func httpAddress() string {
+	if addr := config.GetConf().HTTP.Address; addr != "" {
+		return addr
+	}
+	return "localhost:6060"
+}
+
+// 创建 JWKS 服务，使用单独的 ServeMux，不暴露 pprof 和运行指标
+func newJWKSServer(lc fx.Lifecycle) *http.Server {
+	addr := config.GetConf().HTTP.JWKSAddress
+	if addr == "" {
+		addr = "localhost:6061"
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/jwks.json", jwksHandler)
+	srv := &http.Server{Addr: addr, Handler: mux}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			lis, err := net.Listen("tcp", addr)
+			if err != nil {
+				return fmt.Errorf("failed to listen jwks: %w", err)
+			}
+			go func() {
+				if err := srv.Serve(lis); err != nil && err != http.ErrServerClosed {
+					log.Printf("failed to serve jwks: %v", err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return srv.Shutdown(ctx)
+		},
+	})
+
+	return srv
+}
+
+// 返回校验 token 的公钥，供网关等其他服务独立校验 token
+func jwksHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := utils.JWKS()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	_, _ = w.Write(body)
+}
+
 // 创建监听套接字
 func newListener() (net.Listener, error) {
 	lis, err := net.Listen("tcp", ":50052")
@@ -69,10 +139,12 @@ func newListener() (net.Listener, error) {
 func newGRPCServer(lc fx.Lifecycle, lis net.Listener) *grpc.Server {
 	s := grpc.NewServer(
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
-		grpc.UnaryInterceptor(middleware.AuthInterceptor()), // 认证拦截器
+		grpc.UnaryInterceptor(middleware.AuthInterceptor()),        // 认证拦截器
+		grpc.StreamInterceptor(middleware.AuthStreamInterceptor()), // 流式方法的认证拦截器
 	)
 
 	user.RegisterUserServiceServer(s, &service.UserService{})
+	admin.RegisterAdminServiceServer(s, &service.AdminService{})
 	system.RegisterSystemServiceServer(s, &service.SystemService{})
 	reflection.Register(s)
 
@@ -95,6 +167,42 @@ func newGRPCServer(lc fx.Lifecycle, lis net.Listener) *grpc.Server {
 	return s
 }
 
+// 创建异步词嵌入后台任务
+func newEmbeddingWorker(lc fx.Lifecycle) *worker.EmbeddingWorker {
+	w := worker.NewEmbeddingWorker(config.GetConf().Embedding.Worker)
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			w.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			fmt.Println("Stopping embedding worker")
+			return w.Stop(ctx)
+		},
+	})
+
+	return w
+}
+
+// 创建清理已注销账号的后台任务
+func newPurgeWorker(lc fx.Lifecycle) *worker.PurgeWorker {
+	w := worker.NewPurgeWorker(config.GetConf().Account)
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			w.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			fmt.Println("Stopping purge worker")
+			return w.Stop(ctx)
+		},
+	})
+
+	return w
+}
+
 // 创建 Jaeger 追踪器
 func newTracerProvider(lc fx.Lifecycle) (func(context.Context) error, error) {
 	ctx := context.Background()
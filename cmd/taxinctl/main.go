@@ -0,0 +1,83 @@
+package main
+
+// 运维命令行工具
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/HCH1212/taxin/internal/dao"
+	"github.com/HCH1212/taxin/internal/utils"
+	"github.com/HCH1212/taxin/internal/worker"
+	"github.com/joho/godotenv"
+)
+
+const usage = `usage: taxinctl <command> [flags]
+
+commands:
+  reembed       使用当前配置的词嵌入模型为已有用户重新生成向量
+  import-users  从 CSV 或 JSONL 文件批量导入用户，逐行输出导入结果
+  export-users  按条件导出用户为 JSONL，可以直接用于 import-users
+`
+
+func main() {
+	_ = godotenv.Load()
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// 收到中断信号时保存进度后退出
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var err error
+	switch os.Args[1] {
+	case "reembed":
+		err = runReembed(ctx, os.Args[2:])
+	case "import-users":
+		err = runImportUsers(ctx, os.Args[2:])
+	case "export-users":
+		err = runExportUsers(ctx, os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// 初始化数据库、Redis 和词嵌入服务
+func initDeps() {
+	dao.InitDB()
+	dao.InitRedis()
+	utils.InitEmbedder()
+}
+
+func runReembed(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("reembed", flag.ExitOnError)
+	batchSize := fs.Int("batch", 100, "每批查询的用户数")
+	rate := fs.Float64("rate", 10, "每秒最多处理的用户数，0 表示不限速")
+	reset := fs.Bool("reset", false, "忽略已保存的进度，从头开始")
+	fs.Parse(args)
+
+	initDeps()
+	r := &worker.Reembedder{
+		BatchSize: *batchSize,
+		Rate:      *rate,
+		Reset:     *reset,
+	}
+	progress, err := r.Run(ctx)
+	if err != nil {
+		return fmt.Errorf("reembed stopped after id %d: %w", progress.LastID, err)
+	}
+	log.Printf("reembed: done, %d processed, %d failed", progress.Processed, progress.Failed)
+	return nil
+}
@@ -0,0 +1,217 @@
+package main
+
+// 批量导入导出用户
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/HCH1212/taxin/internal/model"
+	"github.com/HCH1212/taxin/internal/service"
+)
+
+// csvLikeSeparator CSV 中多个喜好之间的分隔符
+const csvLikeSeparator = "|"
+
+func runImportUsers(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import-users", flag.ExitOnError)
+	file := fs.String("file", "", "要导入的 CSV 或 JSONL 文件")
+	format := fs.String("format", "", "文件格式：csv、jsonl，默认按扩展名判断")
+	batchSize := fs.Int("batch", 500, "每批导入的行数")
+	out := fs.String("out", "", "每行导入结果的输出文件（JSONL），默认输出到标准输出")
+	fs.Parse(args)
+
+	if *file == "" {
+		fs.Usage()
+		return errors.New("-file is required")
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+	}
+	in, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	w, closeOut, err := openOutput(*out)
+	if err != nil {
+		return err
+	}
+	defer closeOut()
+
+	initDeps()
+	importer := service.NewUserImporter(*batchSize)
+	enc := json.NewEncoder(w)
+	counts := make(map[string]int)
+	start := time.Now()
+	writeResults := func(results []service.ImportResult) error {
+		for _, r := range results {
+			counts[r.Status]++
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		if len(results) > 0 {
+			rows := results[len(results)-1].Row
+			log.Printf("import-users: %d rows, %d created, %d existing, %d failed, %.1f rows/s",
+				rows, counts[service.ImportStatusCreated], counts[service.ImportStatusExists], counts[service.ImportStatusFailed],
+				float64(rows)/time.Since(start).Seconds())
+		}
+		return nil
+	}
+	err = readImportRows(in, *format, func(row service.ImportRow) error {
+		// 收到中断信号时导入已读取的行后退出
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return writeResults(importer.Add(ctx, row))
+	})
+	// 中断时用新的 context 导入已读取的行，保证结果与数据库一致
+	if flushErr := writeResults(importer.Flush(context.WithoutCancel(ctx))); flushErr != nil && err == nil {
+		err = flushErr
+	}
+	return err
+}
+
+func runExportUsers(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export-users", flag.ExitOnError)
+	out := fs.String("out", "", "输出文件（JSONL），默认输出到标准输出")
+	usernamePrefix := fs.String("username-prefix", "", "只导出用户名以此开头的用户")
+	likeContains := fs.String("like-contains", "", "只导出任意一个喜好包含该文本的用户")
+	createdAfter := fs.String("created-after", "", "注册时间下限（包含），格式 2006-01-02 或 RFC3339")
+	createdBefore := fs.String("created-before", "", "注册时间上限（不包含），格式 2006-01-02 或 RFC3339")
+	includePasswordHash := fs.Bool("include-password-hash", false, "导出密码哈希，用于迁移到其他环境")
+	fs.Parse(args)
+
+	filter := model.UserFilter{
+		UsernamePrefix: *usernamePrefix,
+		LikeContains:   *likeContains,
+	}
+	var err error
+	if filter.CreatedAfter, err = parseTimeFlag(*createdAfter); err != nil {
+		return fmt.Errorf("-created-after: %w", err)
+	}
+	if filter.CreatedBefore, err = parseTimeFlag(*createdBefore); err != nil {
+		return fmt.Errorf("-created-before: %w", err)
+	}
+	w, closeOut, err := openOutput(*out)
+	if err != nil {
+		return err
+	}
+	defer closeOut()
+
+	initDeps()
+	bw := bufio.NewWriter(w)
+	count, err := service.WriteUsersJSONL(ctx, bw, filter, *includePasswordHash)
+	if err != nil {
+		return fmt.Errorf("export stopped after %d users: %w", count, err)
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	log.Printf("export-users: done, %d users exported", count)
+	return nil
+}
+
+// readImportRows 逐行读取 CSV 或 JSONL，不会把整个文件加载到内存
+// CSV 第一行为表头，需要包含 username、like 列以及 password 或 password_hash 列，多个喜好用 | 分隔
+func readImportRows(r io.Reader, format string, fn func(service.ImportRow) error) error {
+	switch format {
+	case "jsonl", "ndjson":
+		dec := json.NewDecoder(r)
+		for line := 1; ; line++ {
+			var row service.ImportRow
+			if err := dec.Decode(&row); err == io.EOF {
+				return nil
+			} else if err != nil {
+				return fmt.Errorf("row %d: %w", line, err)
+			}
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+	case "csv":
+		cr := csv.NewReader(r)
+		header, err := cr.Read()
+		if err != nil {
+			return fmt.Errorf("read csv header: %w", err)
+		}
+		columns := make(map[string]int, len(header))
+		for i, name := range header {
+			columns[strings.ToLower(strings.TrimSpace(name))] = i
+		}
+		if _, ok := columns["username"]; !ok {
+			return errors.New("csv header must contain username column")
+		}
+		if _, ok := columns["like"]; !ok {
+			return errors.New("csv header must contain like column")
+		}
+		field := func(record []string, name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+		for {
+			record, err := cr.Read()
+			if err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			row := service.ImportRow{
+				Username:     field(record, "username"),
+				Password:     field(record, "password"),
+				PasswordHash: field(record, "password_hash"),
+			}
+			for _, like := range strings.Split(field(record, "like"), csvLikeSeparator) {
+				if like = strings.TrimSpace(like); like != "" {
+					row.Like = append(row.Like, like)
+				}
+			}
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unknown format %q, expected csv or jsonl", format)
+	}
+}
+
+// openOutput 打开输出文件，path 为空时使用标准输出
+func openOutput(path string) (io.Writer, func(), error) {
+	if path == "" {
+		return os.Stdout, func() {}, nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, func() {
+		if err := f.Close(); err != nil {
+			log.Printf("close %s: %v", path, err)
+		}
+	}, nil
+}
+
+// parseTimeFlag 解析日期或 RFC3339 时间，为空时返回零值
+func parseTimeFlag(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"gopkg.in/validator.v2"
 	"gopkg.in/yaml.v3"
@@ -19,15 +20,132 @@ var (
 type Config struct {
 	Env string
 
-	SQL    SQL    `yaml:"sql"`
-	Redis  Redis  `yaml:"redis"`
-	Jeager Jeager `yaml:"jeager"`
-	Ollama Ollama `yaml:"ollama"`
+	SQL       SQL       `yaml:"sql"`
+	Redis     Redis     `yaml:"redis"`
+	Jeager    Jeager    `yaml:"jeager"`
+	Ollama    Ollama    `yaml:"ollama"`
+	Embedding Embedding `yaml:"embedding"`
+	HTTP      HTTP      `yaml:"http"`
+
+	JWT           JWT           `yaml:"jwt"`
+	PasswordReset PasswordReset `yaml:"password_reset"`
+	TOTP          TOTP          `yaml:"totp"`
+	LoginLimit    LoginLimit    `yaml:"login_limit"`
+	Account       Account       `yaml:"account"`
+	Notifier      Notifier      `yaml:"notifier"`
+}
+
+// HTTP 旁路 HTTP 服务配置
+type HTTP struct {
+	Address     string `yaml:"address"`      // pprof 和运行指标的监听地址，默认 localhost:6060，不要对外暴露
+	JWKSAddress string `yaml:"jwks_address"` // JWKS 的监听地址，默认 localhost:6061，需要供其他服务校验 token 时可以对外暴露
+}
+
+// JWT token 签发配置
+type JWT struct {
+	AccessTTL  time.Duration `yaml:"access_ttl"`  // access_token 有效期，默认 15m
+	RefreshTTL time.Duration `yaml:"refresh_ttl"` // refresh_token 有效期，默认 720h
+	Issuer     string        `yaml:"issuer"`      // 签发方 iss，默认 taxin
+	Audience   string        `yaml:"audience"`    // 接收方 aud，默认 taxin
+	Leeway     time.Duration `yaml:"leeway"`      // 校验时间时允许的时钟偏差，默认 30s
+	Algorithms []string      `yaml:"algorithms"`  // 允许的签名算法，为空时使用已配置密钥的算法
+	SigningKey string        `yaml:"signing_key"` // 用于签发的密钥 kid，为空时使用 TOKEN_SECRET 以 HS256 签发
+	Keys       []JWTKey      `yaml:"keys"`        // 非对称密钥，轮换时保留旧公钥用于校验
+}
+
+// JWTKey 非对称签名密钥
+type JWTKey struct {
+	ID             string `yaml:"id"`               // kid，写入 token header
+	Algorithm      string `yaml:"algorithm"`        // RS256 | EdDSA
+	PrivateKeyFile string `yaml:"private_key_file"` // PEM 格式私钥，只用于校验的旧密钥可以不配置
+	PublicKeyFile  string `yaml:"public_key_file"`  // PEM 格式公钥，配置了私钥时可以省略
+}
+
+// PasswordReset 重置密码配置
+type PasswordReset struct {
+	TTL        time.Duration `yaml:"ttl"`          // 重置凭证有效期，默认 15m
+	Window     time.Duration `yaml:"window"`       // 统计申请次数的滑动窗口，默认 1h
+	MaxPerUser int           `yaml:"max_per_user"` // 窗口内同一用户名允许的申请次数，默认 3
+	MaxPerIP   int           `yaml:"max_per_ip"`   // 窗口内单个 IP 允许的申请次数，默认 20
+}
+
+// TOTP 两步验证配置
+type TOTP struct {
+	Issuer        string        `yaml:"issuer"`         // 验证器 App 中显示的发行方，默认 taxin
+	EnrollTTL     time.Duration `yaml:"enroll_ttl"`     // 开启两步验证时密钥等待确认的时间，默认 10m
+	ChallengeTTL  time.Duration `yaml:"challenge_ttl"`  // 登录挑战有效期，默认 5m
+	MaxAttempts   int           `yaml:"max_attempts"`   // 每个登录挑战允许的验证次数，默认 5
+	EncryptionKey string        `yaml:"encryption_key"` // base64 编码的 32 字节密钥，用于加密保存两步验证密钥，为空时读取环境变量 TOTP_ENCRYPTION_KEY
+}
+
+// LoginLimit 登录防暴力破解配置
+type LoginLimit struct {
+	Window           time.Duration `yaml:"window"`            // 统计失败次数的滑动窗口，默认 15m
+	MaxPerIP         int           `yaml:"max_per_ip"`        // 窗口内单个 IP 允许的失败次数，默认 50
+	DelayAfter       int           `yaml:"delay_after"`       // 账号失败多少次后开始要求等待，默认 3
+	DelayBase        time.Duration `yaml:"delay_base"`        // 首次等待时间，之后每次失败翻倍，默认 1s
+	DelayMax         time.Duration `yaml:"delay_max"`         // 最长等待时间，默认 30s
+	LockoutThreshold int           `yaml:"lockout_threshold"` // 窗口内账号失败多少次后锁定，默认 10
+	LockoutDuration  time.Duration `yaml:"lockout_duration"`  // 锁定时长，默认 15m
+}
+
+// Account 注销账号配置
+type Account struct {
+	DeleteGracePeriod time.Duration `yaml:"delete_grace_period"` // 注销后可以恢复的时间，超过后彻底删除，默认 720h
+	PurgeInterval     time.Duration `yaml:"purge_interval"`      // 清理任务执行间隔，默认 1h
+	PurgeBatchSize    int           `yaml:"purge_batch_size"`    // 每次清理的用户数，默认 100
+}
+
+// Notifier 通知发送配置
+type Notifier struct {
+	Type     string        `yaml:"type"`      // 发送方式：log、file、memory、webhook，online 环境只允许 webhook
+	FilePath string        `yaml:"file_path"` // type 为 file 时写入的文件
+	URL      string        `yaml:"url"`       // type 为 webhook 时接收通知的投递服务地址，由其发送邮件或短信
+	Secret   string        `yaml:"secret"`    // 请求投递服务时放在 Authorization 头中的令牌
+	Timeout  time.Duration `yaml:"timeout"`   // 请求投递服务的超时时间，默认 5s
 }
 
 type Ollama struct {
-	Address string `yaml:"address"`
-	Model string `yaml:"model"`
+	Address string `yaml:"address"` // ollama 服务地址，例如 http://127.0.0.1:11434
+	Model   string `yaml:"model"`
+}
+
+// Embedding 词嵌入服务配置
+type Embedding struct {
+	Provider     string          `yaml:"provider"`      // 向量服务提供方：ollama、openai、hash
+	Dimension    int             `yaml:"dimension"`     // 向量维度，需与 users.like_embedding 列一致，默认 768
+	Aggregation  string          `yaml:"aggregation"`   // 多个喜好向量的聚合策略：mean、weighted_mean、max，默认 mean
+	Concurrency  int             `yaml:"concurrency"`   // 同时向向量服务发出的最大请求数，默认 4
+	BatchSize    int             `yaml:"batch_size"`    // 批量接口每个请求包含的文本数，默认 16，1 表示不使用批量接口
+	Timeout      time.Duration   `yaml:"timeout"`       // 单次请求超时时间，默认 10s
+	MaxRetries   int             `yaml:"max_retries"`   // 网络错误、429、5xx 时的最大重试次数，0 表示不重试
+	RetryBackoff time.Duration   `yaml:"retry_backoff"` // 首次重试的等待时间，之后指数增长，默认 200ms
+	OpenAI       OpenAI          `yaml:"openai"`
+	Cache        EmbeddingCache  `yaml:"cache"`
+	Async        bool            `yaml:"async"` // 注册时不等待向量生成，由后台任务异步补全
+	Worker       EmbeddingWorker `yaml:"worker"`
+}
+
+// EmbeddingWorker 异步生成向量的后台任务配置
+type EmbeddingWorker struct {
+	PollInterval time.Duration `yaml:"poll_interval"` // 轮询任务表的间隔，默认 1s
+	BatchSize    int           `yaml:"batch_size"`    // 每次领取的任务数，默认 10
+	MaxAttempts  int           `yaml:"max_attempts"`  // 最大尝试次数，超过后任务进入死信状态，默认 5
+	RetryBackoff time.Duration `yaml:"retry_backoff"` // 首次重试的等待时间，之后指数增长，默认 5s
+	LeaseTimeout time.Duration `yaml:"lease_timeout"` // 领取任务后的租约时间，超时未完成会被重新领取，默认 1m
+}
+
+// EmbeddingCache 词嵌入向量的 Redis 缓存配置
+type EmbeddingCache struct {
+	Enabled bool          `yaml:"enabled"`
+	TTL     time.Duration `yaml:"ttl"` // 缓存时间，例如 168h，默认一周
+}
+
+// OpenAI 兼容 OpenAI /v1/embeddings 接口的向量服务配置
+type OpenAI struct {
+	Address string `yaml:"address"` // 接口完整地址，例如 https://api.openai.com/v1/embeddings
+	Model   string `yaml:"model"`
+	APIKey  string `yaml:"api_key"` // 为空时读取环境变量 OPENAI_API_KEY
 }
 
 type Jeager struct {
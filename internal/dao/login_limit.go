@@ -0,0 +1,70 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// loginFailuresKey 登录失败记录，有序集合，score 为失败时间（毫秒）
+func loginFailuresKey(scope, id string) string {
+	return "login:fail:" + scope + ":" + id
+}
+
+// loginDelayKey 存在期间不允许再次尝试登录
+func loginDelayKey(account string) string {
+	return "login:delay:" + account
+}
+
+// loginLockKey 账号锁定标记，值为解锁时间（Unix 秒）
+func loginLockKey(account string) string {
+	return "login:lock:" + account
+}
+
+// RecordLoginFailure 记录一次登录失败，返回滑动窗口内的失败次数
+func RecordLoginFailure(ctx context.Context, scope, id string, window time.Duration) (int64, error) {
+	return RecordWindow(ctx, loginFailuresKey(scope, id), window)
+}
+
+// CountLoginFailures 返回滑动窗口内的失败次数，以及最早一次失败移出窗口还需要的时间
+func CountLoginFailures(ctx context.Context, scope, id string, window time.Duration) (int64, time.Duration, error) {
+	return CountWindow(ctx, loginFailuresKey(scope, id), window)
+}
+
+// ClearLoginFailures 登录成功后清除账号的失败记录和等待时间
+func ClearLoginFailures(ctx context.Context, account string) error {
+	return RedisClient.Del(ctx, loginFailuresKey("user", account), loginDelayKey(account)).Err()
+}
+
+// SetLoginDelay 设置账号下次允许尝试登录前的等待时间
+func SetLoginDelay(ctx context.Context, account string, delay time.Duration) error {
+	return RedisClient.Set(ctx, loginDelayKey(account), 1, delay).Err()
+}
+
+// GetLoginDelay 返回账号还需要等待的时间，不需要等待时返回 0
+func GetLoginDelay(ctx context.Context, account string) (time.Duration, error) {
+	ttl, err := RedisClient.PTTL(ctx, loginDelayKey(account)).Result()
+	if err != nil || ttl < 0 {
+		return 0, err
+	}
+	return ttl, nil
+}
+
+// LockAccount 锁定账号，返回解锁时间
+func LockAccount(ctx context.Context, account string, duration time.Duration) (time.Time, error) {
+	until := time.Now().Add(duration)
+	return until, RedisClient.Set(ctx, loginLockKey(account), until.Unix(), duration).Err()
+}
+
+// GetAccountLock 返回账号的解锁时间，未锁定时返回零值
+func GetAccountLock(ctx context.Context, account string) (time.Time, error) {
+	until, err := RedisClient.Get(ctx, loginLockKey(account)).Int64()
+	if err == redis.Nil {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(until, 0), nil
+}
@@ -0,0 +1,69 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// passwordResetKey 重置凭证摘要到用户 ID 的映射
+func passwordResetKey(tokenHash string) string {
+	return "password:reset:" + tokenHash
+}
+
+// passwordResetUserKey 用户最近一次申请的重置凭证摘要，用于作废之前的凭证
+func passwordResetUserKey(userID string) string {
+	return "password:reset:user:" + userID
+}
+
+// passwordResetRequestsKey 申请重置密码的记录，按用户名或 IP 分别计数
+func passwordResetRequestsKey(scope, id string) string {
+	return "password:reset:requests:" + scope + ":" + id
+}
+
+// RecordPasswordResetRequest 记录一次重置密码申请，返回滑动窗口内的申请次数
+func RecordPasswordResetRequest(ctx context.Context, scope, id string, window time.Duration) (int64, error) {
+	return RecordWindow(ctx, passwordResetRequestsKey(scope, id), window)
+}
+
+// CountPasswordResetRequests 返回滑动窗口内的申请次数，以及最早一次申请移出窗口还需要的时间
+func CountPasswordResetRequests(ctx context.Context, scope, id string, window time.Duration) (int64, time.Duration, error) {
+	return CountWindow(ctx, passwordResetRequestsKey(scope, id), window)
+}
+
+// SavePasswordResetToken 保存重置凭证，同一用户只保留最近一次申请的凭证
+func SavePasswordResetToken(ctx context.Context, tokenHash, userID string, ttl time.Duration) error {
+	if err := DeletePasswordResetToken(ctx, userID); err != nil {
+		return err
+	}
+	pipe := RedisClient.TxPipeline()
+	pipe.Set(ctx, passwordResetKey(tokenHash), userID, ttl)
+	pipe.Set(ctx, passwordResetUserKey(userID), tokenHash, ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// ConsumePasswordResetToken 取出并删除重置凭证，保证凭证只能使用一次，凭证不存在或已过期时返回 redis.Nil
+func ConsumePasswordResetToken(ctx context.Context, tokenHash string) (string, error) {
+	userID, err := RedisClient.GetDel(ctx, passwordResetKey(tokenHash)).Result()
+	if err != nil {
+		return "", err
+	}
+	if err := RedisClient.Del(ctx, passwordResetUserKey(userID)).Err(); err != nil {
+		return "", err
+	}
+	return userID, nil
+}
+
+// DeletePasswordResetToken 作废用户尚未使用的重置凭证
+func DeletePasswordResetToken(ctx context.Context, userID string) error {
+	tokenHash, err := RedisClient.GetDel(ctx, passwordResetUserKey(userID)).Result()
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return RedisClient.Del(ctx, passwordResetKey(tokenHash)).Err()
+}
@@ -0,0 +1,71 @@
+package dao
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RefreshToken 服务端保存的 refresh_token 记录，键为 token 的摘要
+type RefreshToken struct {
+	UserID     string    `json:"user_id"`
+	FamilyID   string    `json:"family_id"` // 同一次登录轮换产生的 refresh_token 属于同一家族
+	Generation int64     `json:"gen"`       // 签发时用户的 token 代数
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+func refreshTokenKey(tokenHash string) string {
+	return "refresh:token:" + tokenHash
+}
+
+// refreshTokenUsedKey 标记 refresh_token 已被轮换，再次使用即视为泄露
+func refreshTokenUsedKey(tokenHash string) string {
+	return "refresh:used:" + tokenHash
+}
+
+func refreshFamilyRevokedKey(familyID string) string {
+	return "refresh:family:revoked:" + familyID
+}
+
+// SaveRefreshToken 保存 refresh_token，到期后自动删除
+func SaveRefreshToken(ctx context.Context, tokenHash string, token RefreshToken) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return RedisClient.Set(ctx, refreshTokenKey(tokenHash), data, time.Until(token.ExpiresAt)).Err()
+}
+
+// GetRefreshToken 获取 refresh_token 记录，不存在或已过期时返回 redis.Nil
+func GetRefreshToken(ctx context.Context, tokenHash string) (*RefreshToken, error) {
+	data, err := RedisClient.Get(ctx, refreshTokenKey(tokenHash)).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	var token RefreshToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkRefreshTokenUsed 原子地标记 refresh_token 已被轮换，返回 false 表示之前已经使用过
+func MarkRefreshTokenUsed(ctx context.Context, tokenHash string, expiresAt time.Time) (bool, error) {
+	return RedisClient.SetNX(ctx, refreshTokenUsedKey(tokenHash), 1, time.Until(expiresAt)).Result()
+}
+
+// RevokeRefreshFamily 作废整个家族的 refresh_token，ttl 不小于家族中 refresh_token 的最长有效期
+func RevokeRefreshFamily(ctx context.Context, familyID string, ttl time.Duration) error {
+	return RedisClient.Set(ctx, refreshFamilyRevokedKey(familyID), 1, ttl).Err()
+}
+
+// IsRefreshFamilyRevoked 判断家族是否已被作废
+func IsRefreshFamilyRevoked(ctx context.Context, familyID string) (bool, error) {
+	err := RedisClient.Get(ctx, refreshFamilyRevokedKey(familyID)).Err()
+	if err == redis.Nil {
+		return false, nil
+	}
+	return err == nil, err
+}
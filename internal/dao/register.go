@@ -0,0 +1,31 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// registerLockKey 注册同一个用户名时的分布式锁
+func registerLockKey(username string) string {
+	return "register:lock:" + username
+}
+
+// releaseLockScript 只有锁仍属于自己时才删除，避免锁过期后误删其他请求的锁
+var releaseLockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// AcquireRegisterLock 尝试获取用户名的注册锁，已被其他请求持有时返回 false
+func AcquireRegisterLock(ctx context.Context, username, token string, ttl time.Duration) (bool, error) {
+	return RedisClient.SetNX(ctx, registerLockKey(username), token, ttl).Result()
+}
+
+// ReleaseRegisterLock 释放注册锁，token 与获取时一致
+func ReleaseRegisterLock(ctx context.Context, username, token string) error {
+	return releaseLockScript.Run(ctx, RedisClient, []string{registerLockKey(username)}, token).Err()
+}
@@ -0,0 +1,154 @@
+package dao
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Session 一次登录对应的会话，ID 与该次登录的 refresh_token 家族一致
+type Session struct {
+	ID         string
+	UserID     string
+	UserAgent  string
+	IP         string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+}
+
+func sessionKey(sessionID string) string {
+	return "session:" + sessionID
+}
+
+// userSessionsKey 用户所有会话 ID 的集合
+func userSessionsKey(userID string) string {
+	return "session:user:" + userID
+}
+
+// touchSessionScript 只在会话仍存在时更新最后活跃时间，避免重新创建已撤销的会话
+var touchSessionScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	redis.call('HSET', KEYS[1], 'last_seen_at', ARGV[1])
+	return 1
+end
+return 0
+`)
+
+// CreateSession 保存会话，ttl 与 refresh_token 有效期一致
+func CreateSession(ctx context.Context, session Session, ttl time.Duration) error {
+	_, err := RedisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, sessionKey(session.ID), map[string]interface{}{
+			"user_id":      session.UserID,
+			"user_agent":   session.UserAgent,
+			"ip":           session.IP,
+			"created_at":   session.CreatedAt.Unix(),
+			"last_seen_at": session.LastSeenAt.Unix(),
+		})
+		pipe.Expire(ctx, sessionKey(session.ID), ttl)
+		pipe.SAdd(ctx, userSessionsKey(session.UserID), session.ID)
+		pipe.Expire(ctx, userSessionsKey(session.UserID), ttl)
+		return nil
+	})
+	return err
+}
+
+// GetSession 获取会话，不存在或已撤销时返回 redis.Nil
+func GetSession(ctx context.Context, sessionID string) (*Session, error) {
+	fields, err := RedisClient.HGetAll(ctx, sessionKey(sessionID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, redis.Nil
+	}
+	return parseSession(sessionID, fields), nil
+}
+
+// TouchSession 更新会话最后活跃时间，返回 false 表示会话不存在或已撤销
+func TouchSession(ctx context.Context, sessionID string, at time.Time) (bool, error) {
+	n, err := touchSessionScript.Run(ctx, RedisClient, []string{sessionKey(sessionID)}, at.Unix()).Int()
+	return n == 1, err
+}
+
+// ExtendSession 延长会话有效期，refresh_token 轮换时调用
+func ExtendSession(ctx context.Context, userID, sessionID string, ttl time.Duration) error {
+	_, err := RedisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Expire(ctx, sessionKey(sessionID), ttl)
+		pipe.Expire(ctx, userSessionsKey(userID), ttl)
+		return nil
+	})
+	return err
+}
+
+// ListSessions 获取用户所有未过期的会话，顺带清理集合中已过期的会话 ID
+func ListSessions(ctx context.Context, userID string) ([]Session, error) {
+	ids, err := RedisClient.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	cmds := make([]*redis.StringStringMapCmd, len(ids))
+	_, err = RedisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, id := range ids {
+			cmds[i] = pipe.HGetAll(ctx, sessionKey(id))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]Session, 0, len(ids))
+	var expired []interface{}
+	for i, cmd := range cmds {
+		fields := cmd.Val()
+		if len(fields) == 0 {
+			expired = append(expired, ids[i])
+			continue
+		}
+		sessions = append(sessions, *parseSession(ids[i], fields))
+	}
+	if len(expired) > 0 {
+		if err := RedisClient.SRem(ctx, userSessionsKey(userID), expired...).Err(); err != nil {
+			return nil, err
+		}
+	}
+	return sessions, nil
+}
+
+// DeleteSession 撤销会话
+func DeleteSession(ctx context.Context, userID, sessionID string) error {
+	_, err := RedisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, sessionKey(sessionID))
+		pipe.SRem(ctx, userSessionsKey(userID), sessionID)
+		return nil
+	})
+	return err
+}
+
+// DeleteUserSessions 撤销用户所有会话，返回被撤销的会话 ID
+func DeleteUserSessions(ctx context.Context, userID string) ([]string, error) {
+	ids, err := RedisClient.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(ids)+1)
+	for _, id := range ids {
+		keys = append(keys, sessionKey(id))
+	}
+	keys = append(keys, userSessionsKey(userID))
+	return ids, RedisClient.Del(ctx, keys...).Err()
+}
+
+func parseSession(sessionID string, fields map[string]string) *Session {
+	createdAt, _ := strconv.ParseInt(fields["created_at"], 10, 64)
+	lastSeenAt, _ := strconv.ParseInt(fields["last_seen_at"], 10, 64)
+	return &Session{
+		ID:         sessionID,
+		UserID:     fields["user_id"],
+		UserAgent:  fields["user_agent"],
+		IP:         fields["ip"],
+		CreatedAt:  time.Unix(createdAt, 0),
+		LastSeenAt: time.Unix(lastSeenAt, 0),
+	}
+}
@@ -0,0 +1,48 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// tokenGenerationKey 用户当前的 token 代数，签发时写入 token，校验时不一致即视为已失效
+func tokenGenerationKey(userID string) string {
+	return "token:generation:" + userID
+}
+
+// GetTokenGeneration 获取用户当前的 token 代数，未设置时为 0
+func GetTokenGeneration(ctx context.Context, userID string) (int64, error) {
+	generation, err := RedisClient.Get(ctx, tokenGenerationKey(userID)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return generation, err
+}
+
+// BumpTokenGeneration 增加用户的 token 代数，使该用户已签发的所有 token 失效，返回新的代数
+func BumpTokenGeneration(ctx context.Context, userID string) (int64, error) {
+	return RedisClient.Incr(ctx, tokenGenerationKey(userID)).Result()
+}
+
+// revokedTokenKey 已退出登录的 access_token，按 jti 记录
+func revokedTokenKey(jti string) string {
+	return "token:revoked:" + jti
+}
+
+// RevokeToken 将 access_token 加入黑名单，直到 token 过期
+func RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		// 已过期的 token 不会通过校验，无需记录
+		return nil
+	}
+	return RedisClient.Set(ctx, revokedTokenKey(jti), 1, ttl).Err()
+}
+
+// IsTokenRevoked 判断 access_token 是否在黑名单中
+func IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	n, err := RedisClient.Exists(ctx, revokedTokenKey(jti)).Result()
+	return n > 0, err
+}
@@ -0,0 +1,77 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevokeToken(t *testing.T) {
+	setupTestRedis(t)
+	ctx := context.Background()
+
+	revoked, err := IsTokenRevoked(ctx, "jti-1")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	require.NoError(t, RevokeToken(ctx, "jti-1", time.Now().Add(time.Minute)))
+	revoked, err = IsTokenRevoked(ctx, "jti-1")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+	// 黑名单只保留到 token 过期
+	ttl, err := RedisClient.TTL(ctx, revokedTokenKey("jti-1")).Result()
+	require.NoError(t, err)
+	assert.Greater(t, ttl, time.Duration(0))
+	assert.LessOrEqual(t, ttl, time.Minute)
+
+	// 已过期的 token 无需记录
+	require.NoError(t, RevokeToken(ctx, "jti-2", time.Now().Add(-time.Minute)))
+	revoked, err = IsTokenRevoked(ctx, "jti-2")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestTokenGeneration(t *testing.T) {
+	setupTestRedis(t)
+	ctx := context.Background()
+
+	generation, err := GetTokenGeneration(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Zero(t, generation)
+	generation, err = BumpTokenGeneration(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), generation)
+	generation, err = GetTokenGeneration(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), generation)
+}
+
+func TestMarkRefreshTokenUsed(t *testing.T) {
+	setupTestRedis(t)
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Hour)
+	require.NoError(t, SaveRefreshToken(ctx, "hash-1", RefreshToken{UserID: "user-1", FamilyID: "family-1", ExpiresAt: expiresAt}))
+
+	record, err := GetRefreshToken(ctx, "hash-1")
+	require.NoError(t, err)
+	assert.Equal(t, "family-1", record.FamilyID)
+
+	// 只有第一次使用成功，再次使用说明发生了重放
+	first, err := MarkRefreshTokenUsed(ctx, "hash-1", expiresAt)
+	require.NoError(t, err)
+	assert.True(t, first)
+	first, err = MarkRefreshTokenUsed(ctx, "hash-1", expiresAt)
+	require.NoError(t, err)
+	assert.False(t, first)
+
+	revoked, err := IsRefreshFamilyRevoked(ctx, "family-1")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+	require.NoError(t, RevokeRefreshFamily(ctx, "family-1", time.Hour))
+	revoked, err = IsRefreshFamilyRevoked(ctx, "family-1")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+}
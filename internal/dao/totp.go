@@ -0,0 +1,85 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// totpPendingKey 等待确认的两步验证密钥
+func totpPendingKey(userID string) string {
+	return "totp:pending:" + userID
+}
+
+// totpChallengeKey 登录时密码校验通过后生成的挑战，按挑战摘要记录用户 ID 和已尝试次数
+func totpChallengeKey(challengeHash string) string {
+	return "totp:challenge:" + challengeHash
+}
+
+// totpUsedKey 已使用过的时间步，同一验证码不能重复使用
+func totpUsedKey(userID string, step int64) string {
+	return "totp:used:" + userID + ":" + strconv.FormatInt(step, 10)
+}
+
+// SavePendingTOTP 保存等待确认的两步验证密钥，重新申请会覆盖之前的密钥
+func SavePendingTOTP(ctx context.Context, userID, secret string, ttl time.Duration) error {
+	return RedisClient.Set(ctx, totpPendingKey(userID), secret, ttl).Err()
+}
+
+// GetPendingTOTP 获取等待确认的两步验证密钥，不存在或已过期时返回 redis.Nil
+func GetPendingTOTP(ctx context.Context, userID string) (string, error) {
+	return RedisClient.Get(ctx, totpPendingKey(userID)).Result()
+}
+
+// DeletePendingTOTP 删除等待确认的两步验证密钥
+func DeletePendingTOTP(ctx context.Context, userID string) error {
+	return RedisClient.Del(ctx, totpPendingKey(userID)).Err()
+}
+
+// SaveTOTPChallenge 保存登录挑战
+func SaveTOTPChallenge(ctx context.Context, challengeHash, userID string, ttl time.Duration) error {
+	_, err := RedisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, totpChallengeKey(challengeHash), "user_id", userID, "attempts", 0)
+		pipe.Expire(ctx, totpChallengeKey(challengeHash), ttl)
+		return nil
+	})
+	return err
+}
+
+// attemptTOTPChallengeScript 挑战存在时增加尝试次数并返回用户 ID 和尝试次数，不存在时返回 nil
+// 读取和计数在同一个脚本中完成，挑战在两步之间过期时不会重新创建没有过期时间的键
+var attemptTOTPChallengeScript = redis.NewScript(`
+local userID = redis.call('HGET', KEYS[1], 'user_id')
+if not userID then
+	return false
+end
+local attempts = redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+return {userID, attempts}
+`)
+
+// AttemptTOTPChallenge 记录一次验证尝试，返回挑战对应的用户 ID 和包括本次在内的尝试次数，挑战不存在时返回 redis.Nil
+func AttemptTOTPChallenge(ctx context.Context, challengeHash string) (string, int64, error) {
+	result, err := attemptTOTPChallengeScript.Run(ctx, RedisClient, []string{totpChallengeKey(challengeHash)}).Slice()
+	if err != nil {
+		return "", 0, err
+	}
+	if len(result) != 2 {
+		return "", 0, fmt.Errorf("unexpected totp challenge result: %v", result)
+	}
+	userID, _ := result[0].(string)
+	attempts, _ := result[1].(int64)
+	return userID, attempts, nil
+}
+
+// DeleteTOTPChallenge 删除登录挑战，验证成功或尝试次数用尽后调用
+func DeleteTOTPChallenge(ctx context.Context, challengeHash string) error {
+	return RedisClient.Del(ctx, totpChallengeKey(challengeHash)).Err()
+}
+
+// MarkTOTPStepUsed 原子地标记时间步已使用，返回 false 表示该验证码已经使用过
+func MarkTOTPStepUsed(ctx context.Context, userID string, step int64, ttl time.Duration) (bool, error) {
+	return RedisClient.SetNX(ctx, totpUsedKey(userID, step), 1, ttl).Result()
+}
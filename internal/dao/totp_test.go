@@ -0,0 +1,69 @@
+package dao
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupTestRedis 设置了 TEST_REDIS_ADDR 时连接该 Redis，连接不上时跳过测试；未设置时使用 miniredis
+func setupTestRedis(t *testing.T) {
+	addr := os.Getenv("TEST_REDIS_ADDR")
+	if addr == "" {
+		addr = miniredis.RunT(t).Addr()
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		t.Skipf("redis not available at %s: %v", addr, err)
+	}
+	prev := RedisClient
+	RedisClient = client
+	t.Cleanup(func() {
+		RedisClient = prev
+		client.Close()
+	})
+}
+
+func TestAttemptTOTPChallenge(t *testing.T) {
+	setupTestRedis(t)
+	ctx := context.Background()
+	challengeHash := "test-" + time.Now().Format(time.RFC3339Nano)
+	t.Cleanup(func() { DeleteTOTPChallenge(ctx, challengeHash) })
+
+	assert.NoError(t, SaveTOTPChallenge(ctx, challengeHash, "user-1", time.Minute))
+	for want := int64(1); want <= 3; want++ {
+		userID, attempts, err := AttemptTOTPChallenge(ctx, challengeHash)
+		assert.NoError(t, err)
+		assert.Equal(t, "user-1", userID)
+		assert.Equal(t, want, attempts)
+	}
+	// 计数不能清除过期时间
+	ttl, err := RedisClient.TTL(ctx, totpChallengeKey(challengeHash)).Result()
+	assert.NoError(t, err)
+	assert.Greater(t, ttl, time.Duration(0))
+}
+
+func TestAttemptTOTPChallengeExpired(t *testing.T) {
+	setupTestRedis(t)
+	ctx := context.Background()
+	challengeHash := "test-expired-" + time.Now().Format(time.RFC3339Nano)
+	t.Cleanup(func() { DeleteTOTPChallenge(ctx, challengeHash) })
+
+	assert.NoError(t, SaveTOTPChallenge(ctx, challengeHash, "user-1", time.Minute))
+	// 模拟挑战在两次尝试之间过期
+	assert.NoError(t, RedisClient.Del(ctx, totpChallengeKey(challengeHash)).Err())
+	_, _, err := AttemptTOTPChallenge(ctx, challengeHash)
+	assert.ErrorIs(t, err, redis.Nil)
+	// 过期的挑战不能被计数重新创建
+	exists, err := RedisClient.Exists(ctx, totpChallengeKey(challengeHash)).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), exists)
+}
@@ -0,0 +1,48 @@
+package dao
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// 滑动窗口计数，key 为有序集合，score 为事件时间（毫秒），由调用方决定键名
+
+// windowMember 有序集合成员需要唯一，同一时刻的多次事件不能互相覆盖
+func windowMember(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10) + "-" + strconv.FormatInt(rand.Int63(), 36)
+}
+
+// RecordWindow 在 key 中记录一次事件，返回滑动窗口内的事件数
+func RecordWindow(ctx context.Context, key string, window time.Duration) (int64, error) {
+	now := time.Now()
+	pipe := RedisClient.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.Add(-window).UnixMilli(), 10))
+	pipe.ZAdd(ctx, key, &redis.Z{Score: float64(now.UnixMilli()), Member: windowMember(now)})
+	count := pipe.ZCard(ctx, key)
+	pipe.Expire(ctx, key, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return count.Val(), nil
+}
+
+// CountWindow 返回 key 中滑动窗口内的事件数，以及最早一次事件移出窗口还需要的时间
+func CountWindow(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	now := time.Now()
+	pipe := RedisClient.Pipeline()
+	pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.Add(-window).UnixMilli(), 10))
+	count := pipe.ZCard(ctx, key)
+	oldest := pipe.ZRangeWithScores(ctx, key, 0, 0)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, 0, err
+	}
+	var retryAfter time.Duration
+	if z := oldest.Val(); len(z) > 0 {
+		retryAfter = time.UnixMilli(int64(z[0].Score)).Add(window).Sub(now)
+	}
+	return count.Val(), retryAfter, nil
+}
@@ -4,53 +4,133 @@ package middleware
 
 import (
 	"context"
-	"errors"
 	"strings"
+	"time"
 
+	"github.com/HCH1212/taxin/internal/dao"
 	"github.com/HCH1212/taxin/internal/utils"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
-// AuthInterceptor 是一个 gRPC 一元拦截器，用于鉴权
+// AuthInterceptor 是一个 gRPC 一元拦截器，按 Policies 中声明的策略鉴权
 func AuthInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		// 只拦截 GetUserInfo 方法
-		if info.FullMethod != "/user.UserService/GetUserInfo" {
-			return handler(ctx, req)
+		ctx, err := authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
 		}
+		// 调用下一个处理程序
+		return handler(ctx, req)
+	}
+}
 
-		// 从元数据中获取 Authorization 头
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return nil, errors.New("missing metadata")
+// AuthStreamInterceptor 是一个 gRPC 流式拦截器，与 AuthInterceptor 使用相同的策略
+func AuthStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
 		}
+		return handler(srv, &authServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
 
-		// 获取 token
-		authHeader := md.Get("authorization")
-		if len(authHeader) == 0 {
-			return nil, errors.New("missing authorization token")
-		}
+// authServerStream 替换流的上下文，使处理程序能取到用户信息
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
 
-		tokenString := authHeader[0]
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
+}
 
-		// 去除 "Bearer " 前缀（忽略大小写）
-		const bearerPrefix = "Bearer "
-		if len(tokenString) > len(bearerPrefix) &&
-			strings.EqualFold(tokenString[:len(bearerPrefix)], bearerPrefix) {
-			tokenString = tokenString[len(bearerPrefix):]
-		}
+// authorize 查找方法的访问策略并校验，未声明策略的方法一律拒绝
+func authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	policy, ok := Policies[fullMethod]
+	if !ok {
+		return nil, status.Errorf(codes.PermissionDenied, "no access policy for %s", fullMethod)
+	}
+	if policy.Level == LevelPublic {
+		return ctx, nil
+	}
+
+	claims, err := authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.Allows(claims.Role) {
+		return nil, status.Error(codes.PermissionDenied, "permission denied")
+	}
+
+	// 将用户 ID 添加到上下文
+	ctx = context.WithValue(ctx, "user_id", claims.UserID)
+	// 退出登录时需要 token 的 jti 和过期时间
+	ctx = context.WithValue(ctx, "claims", claims)
+	return ctx, nil
+}
 
-		// 解析 token
-		claims, err := utils.ParseAccessToken(tokenString)
+// authenticate 解析并校验请求中的 access_token，认证失败时返回 Unauthenticated
+func authenticate(ctx context.Context) (*utils.Claims, error) {
+	// 从元数据中获取 Authorization 头
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing metadata")
+	}
+
+	// 获取 token
+	authHeader := md.Get("authorization")
+	if len(authHeader) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing authorization token")
+	}
+
+	tokenString := authHeader[0]
+
+	// 去除 "Bearer " 前缀（忽略大小写）
+	const bearerPrefix = "Bearer "
+	if len(tokenString) > len(bearerPrefix) &&
+		strings.EqualFold(tokenString[:len(bearerPrefix)], bearerPrefix) {
+		tokenString = tokenString[len(bearerPrefix):]
+	}
+
+	// 解析 token
+	claims, err := utils.ParseAccessToken(tokenString)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	// 已退出登录的 token
+	if claims.ID != "" {
+		revoked, err := dao.IsTokenRevoked(ctx, claims.ID)
 		if err != nil {
 			return nil, err
 		}
+		if revoked {
+			return nil, status.Error(codes.Unauthenticated, "token has been revoked")
+		}
+	}
 
-		// 将用户 ID 添加到上下文
-		ctx = context.WithValue(ctx, "user_id", claims.UserID)
+	// 修改密码、退出所有登录等操作后，之前签发的 token 失效
+	generation, err := dao.GetTokenGeneration(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Generation != generation {
+		return nil, status.Error(codes.Unauthenticated, "token has been revoked")
+	}
 
-		// 调用下一个处理程序
-		return handler(ctx, req)
+	// 会话已被撤销，同时刷新会话的最后活跃时间
+	if claims.SessionID != "" {
+		active, err := dao.TouchSession(ctx, claims.SessionID, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if !active {
+			return nil, status.Error(codes.Unauthenticated, "session has been revoked")
+		}
 	}
+	return claims, nil
 }
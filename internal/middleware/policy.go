@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"github.com/HCH1212/taxin/api/pb/admin"
+	"github.com/HCH1212/taxin/api/pb/system"
+	"github.com/HCH1212/taxin/api/pb/user"
+	"github.com/HCH1212/taxin/internal/model"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+)
+
+// Level 调用方法需要的认证级别
+type Level int
+
+const (
+	LevelPublic        Level = iota + 1 // 无需登录
+	LevelAuthenticated                  // 需要登录
+	LevelRoles                          // 需要登录且拥有指定角色之一
+)
+
+// Policy 方法的访问策略
+type Policy struct {
+	Level Level
+	Roles []string // Level 为 LevelRoles 时允许的角色
+}
+
+var (
+	public        = Policy{Level: LevelPublic}
+	authenticated = Policy{Level: LevelAuthenticated}
+)
+
+// requireRoles 需要登录且拥有指定角色之一
+func requireRoles(roles ...string) Policy {
+	return Policy{Level: LevelRoles, Roles: roles}
+}
+
+// Allows 判断角色是否满足策略，调用前需要已经通过登录校验
+func (p Policy) Allows(role string) bool {
+	if p.Level != LevelRoles {
+		return true
+	}
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Policies 方法全名到访问策略的映射，未列出的方法一律拒绝，新增方法时需要在这里声明
+var Policies = map[string]Policy{
+	user.UserService_Register_FullMethodName:                  public,
+	user.UserService_Login_FullMethodName:                     public,
+	user.UserService_VerifyTOTP_FullMethodName:                public,
+	user.UserService_RefreshToken_FullMethodName:              public,
+	user.UserService_RequestPasswordReset_FullMethodName:      public,
+	user.UserService_ConfirmPasswordReset_FullMethodName:      public,
+	user.UserService_RestoreAccount_FullMethodName:            public,
+	user.UserService_EnrollTOTP_FullMethodName:                authenticated,
+	user.UserService_ConfirmTOTP_FullMethodName:               authenticated,
+	user.UserService_Logout_FullMethodName:                    authenticated,
+	user.UserService_LogoutAllSessions_FullMethodName:         authenticated,
+	user.UserService_ListSessions_FullMethodName:              authenticated,
+	user.UserService_RevokeSession_FullMethodName:             authenticated,
+	user.UserService_DeleteAccount_FullMethodName:             authenticated,
+	user.UserService_ExportMyData_FullMethodName:              authenticated,
+	user.UserService_GetUserInfo_FullMethodName:               authenticated,
+	user.UserService_UpdateProfile_FullMethodName:             authenticated,
+	user.UserService_ChangePassword_FullMethodName:            authenticated,
+	user.UserService_FindSimilarUsers_FullMethodName:          authenticated,
+	user.UserService_SearchUsersByInterest_FullMethodName:     authenticated,
+	user.UserService_FindUsersBySharedInterest_FullMethodName: authenticated,
+	user.UserService_FindUsersByLike_FullMethodName:           authenticated,
+
+	admin.AdminService_ListUsers_FullMethodName:   requireRoles(model.RoleAdmin),
+	admin.AdminService_GetUser_FullMethodName:     requireRoles(model.RoleAdmin),
+	admin.AdminService_DisableUser_FullMethodName: requireRoles(model.RoleAdmin),
+	admin.AdminService_EnableUser_FullMethodName:  requireRoles(model.RoleAdmin),
+	admin.AdminService_ForceLogout_FullMethodName: requireRoles(model.RoleAdmin),
+	admin.AdminService_ImportUsers_FullMethodName: requireRoles(model.RoleAdmin),
+	admin.AdminService_ExportUsers_FullMethodName: requireRoles(model.RoleAdmin),
+
+	// SendFile 读取服务器本地文件，只对管理员开放
+	system.SystemService_SendFile_FullMethodName: requireRoles(model.RoleAdmin),
+
+	reflectionv1.ServerReflection_ServerReflectionInfo_FullMethodName:      public,
+	reflectionv1alpha.ServerReflection_ServerReflectionInfo_FullMethodName: public,
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/HCH1212/taxin/api/pb/admin"
+	"github.com/HCH1212/taxin/api/pb/system"
+	"github.com/HCH1212/taxin/api/pb/user"
+	"github.com/HCH1212/taxin/internal/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 所有注册的方法都必须声明访问策略，否则会被默认拒绝
+func TestPoliciesCoverAllMethods(t *testing.T) {
+	for _, desc := range []grpc.ServiceDesc{user.UserService_ServiceDesc, admin.AdminService_ServiceDesc, system.SystemService_ServiceDesc} {
+		for _, m := range desc.Methods {
+			name := "/" + desc.ServiceName + "/" + m.MethodName
+			if _, ok := Policies[name]; !ok {
+				t.Errorf("missing policy for %s", name)
+			}
+		}
+		for _, s := range desc.Streams {
+			name := "/" + desc.ServiceName + "/" + s.StreamName
+			if _, ok := Policies[name]; !ok {
+				t.Errorf("missing policy for %s", name)
+			}
+		}
+	}
+}
+
+func TestAuthorizeDeniesUnknownMethod(t *testing.T) {
+	_, err := authorize(context.Background(), "/user.UserService/NotDeclared")
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("code = %v, want %v", status.Code(err), codes.PermissionDenied)
+	}
+}
+
+func TestAuthorizeRequiresToken(t *testing.T) {
+	_, err := authorize(context.Background(), user.UserService_GetUserInfo_FullMethodName)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("code = %v, want %v", status.Code(err), codes.Unauthenticated)
+	}
+	if _, err := authorize(context.Background(), user.UserService_Login_FullMethodName); err != nil {
+		t.Errorf("public method rejected: %v", err)
+	}
+}
+
+func TestPolicyAllows(t *testing.T) {
+	admin := requireRoles("admin", "support")
+	if !admin.Allows("support") || admin.Allows("user") || admin.Allows("") {
+		t.Error("role policy mismatch")
+	}
+	if !authenticated.Allows("user") || !public.Allows("") {
+		t.Error("non-role policies should allow any role")
+	}
+}
+
+// 读取本地文件和后台查询接口只对管理员开放
+func TestAdminOnlyPolicies(t *testing.T) {
+	for _, method := range []string{
+		system.SystemService_SendFile_FullMethodName,
+		admin.AdminService_ListUsers_FullMethodName,
+		admin.AdminService_GetUser_FullMethodName,
+	} {
+		policy := Policies[method]
+		if policy.Level != LevelRoles || !policy.Allows(model.RoleAdmin) ||
+			policy.Allows(model.RoleSupport) || policy.Allows(model.RoleUser) {
+			t.Errorf("%s should be admin only", method)
+		}
+	}
+}
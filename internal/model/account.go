@@ -0,0 +1,84 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DefaultDeleteGracePeriod 未配置 account.delete_grace_period 时注销账号后可以恢复的时间
+const DefaultDeleteGracePeriod = 30 * 24 * time.Hour
+
+// SoftDeleteUser 软删除用户，宽限期内可以恢复
+func SoftDeleteUser(db *gorm.DB, userID string) error {
+	result := db.Where("user_id = ?", userID).Delete(&User{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetDeletedUser 获取已软删除的用户，userID 和 username 二选一
+func GetDeletedUser(db *gorm.DB, userID, username string) (*User, error) {
+	query := db.Unscoped().Where("deleted_at IS NOT NULL")
+	if username != "" {
+		query = query.Where("username = ?", username)
+	} else {
+		query = query.Where("user_id = ?", userID)
+	}
+	var user User
+	if err := query.First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// RestoreUser 恢复在 deletedAfter 之后软删除的用户，返回 false 表示用户不存在或已超过宽限期
+func RestoreUser(db *gorm.DB, userID string, deletedAfter time.Time) (bool, error) {
+	result := db.Unscoped().Model(&User{}).
+		Where("user_id = ? AND deleted_at IS NOT NULL AND deleted_at >= ?", userID, deletedAfter).
+		Update("deleted_at", nil)
+	return result.RowsAffected == 1, result.Error
+}
+
+// ListUsersToPurge 获取在 deletedBefore 之前软删除、需要彻底删除的用户 ID
+func ListUsersToPurge(db *gorm.DB, deletedBefore time.Time, limit int) ([]string, error) {
+	var userIDs []string
+	err := db.Unscoped().Model(&User{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+		Order("deleted_at").
+		Limit(limit).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// PurgeUser 彻底删除用户及其喜好向量、词嵌入任务和恢复码，只删除仍处于软删除状态的用户
+// 审计日志需要保留，只清空其中的 user_id，使其无法再关联到该用户
+func PurgeUser(db *gorm.DB, userID string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 锁定用户行，避免与恢复账号并发
+		var user User
+		err := tx.Unscoped().
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+			First(&user).Error
+		if err != nil {
+			return err
+		}
+		// user_likes 等表引用 users，需要先于用户删除
+		for _, m := range []interface{}{&UserLike{}, &EmbeddingJob{}, &RecoveryCode{}} {
+			if err := tx.Unscoped().Where("user_id = ?", userID).Delete(m).Error; err != nil {
+				return err
+			}
+		}
+		err = tx.Unscoped().Model(&AuditEvent{}).Where("user_id = ?", userID).Update("user_id", nil).Error
+		if err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&user).Error
+	})
+}
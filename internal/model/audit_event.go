@@ -0,0 +1,45 @@
+package model
+
+import (
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// 审计事件类型
+const (
+	AuditEventLoginLocked      = "login_locked"       // 账号因多次登录失败被临时锁定
+	AuditEventLoginRateLimited = "login_rate_limited" // 来源 IP 登录失败次数过多被限流
+	AuditEventUserDisabled     = "user_disabled"      // 管理员禁用用户
+	AuditEventUserEnabled      = "user_enabled"       // 管理员解除禁用
+	AuditEventForceLogout      = "force_logout"       // 管理员强制用户退出所有登录
+	AuditEventAccountDeleted   = "account_deleted"    // 用户注销账号
+	AuditEventAccountRestored  = "account_restored"   // 用户在宽限期内恢复账号
+	AuditEventDataExported     = "data_exported"      // 用户导出个人数据
+	AuditEventUsersImported    = "users_imported"     // 管理员批量导入用户
+	AuditEventUsersExported    = "users_exported"     // 管理员批量导出用户
+)
+
+// AuditEvent 安全相关的审计日志
+type AuditEvent struct {
+	gorm.Model
+	UserID string         `json:"user_id" gorm:"type:varchar(255);index"` // 相关用户，无法确定用户时为空
+	Event  string         `json:"event" gorm:"type:varchar(64);not null"` // 事件类型
+	IP     string         `json:"ip" gorm:"type:varchar(64)"`             // 客户端 IP
+	Detail datatypes.JSON `json:"detail" gorm:"type:jsonb"`               // 事件详情
+}
+
+func (e *AuditEvent) TableName() string {
+	return "audit_events"
+}
+
+// CreateAuditEvent 写入审计日志
+func CreateAuditEvent(db *gorm.DB, event *AuditEvent) error {
+	return db.Create(event).Error
+}
+
+// ListAuditEvents 按主键游标分页获取用户的审计日志，afterID 为上一页最后一条的主键
+func ListAuditEvents(db *gorm.DB, userID string, afterID uint, limit int) ([]AuditEvent, error) {
+	var events []AuditEvent
+	err := db.Where("user_id = ? AND id > ?", userID, afterID).Order("id").Limit(limit).Find(&events).Error
+	return events, err
+}
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 词嵌入任务状态
+const (
+	EmbeddingJobPending = "pending" // 等待执行或等待重试
+	EmbeddingJobDone    = "done"    // 执行成功
+	EmbeddingJobDead    = "dead"    // 超过最大重试次数，进入死信状态，需要人工处理
+)
+
+// EmbeddingJob 异步生成用户喜好向量的任务
+type EmbeddingJob struct {
+	gorm.Model
+	UserID    string    `json:"user_id" gorm:"type:varchar(255);not null;index"`         // 用户分布式 ID
+	Status    string    `json:"status" gorm:"type:varchar(16);not null;default:pending"` // 任务状态
+	Attempts  int       `json:"attempts" gorm:"not null;default:0"`                      // 已尝试次数
+	NextRunAt time.Time `json:"next_run_at" gorm:"not null"`                             // 下次可执行时间，执行中的任务用作租约到期时间
+	LastError string    `json:"last_error" gorm:"type:text"`                             // 最近一次失败原因
+}
+
+func (j *EmbeddingJob) TableName() string {
+	return "embedding_jobs"
+}
+
+// CreateEmbeddingJob 为用户创建一个立即可执行的词嵌入任务
+func CreateEmbeddingJob(db *gorm.DB, userID string) error {
+	return db.Create(&EmbeddingJob{
+		UserID:    userID,
+		Status:    EmbeddingJobPending,
+		NextRunAt: time.Now(),
+	}).Error
+}
+
+// CreateEmbeddingJobs 为多个用户批量创建立即可执行的词嵌入任务
+func CreateEmbeddingJobs(db *gorm.DB, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	jobs := make([]EmbeddingJob, 0, len(userIDs))
+	for _, userID := range userIDs {
+		jobs = append(jobs, EmbeddingJob{
+			UserID:    userID,
+			Status:    EmbeddingJobPending,
+			NextRunAt: now,
+		})
+	}
+	return db.Create(&jobs).Error
+}
+
+// ClaimEmbeddingJobs 领取最多 limit 个到期任务，领取后任务在 lease 时间内不会被其他 worker 领取
+// 进程在执行过程中退出时，租约到期后任务会被重新领取
+func ClaimEmbeddingJobs(db *gorm.DB, limit int, lease time.Duration) ([]EmbeddingJob, error) {
+	var jobs []EmbeddingJob
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_run_at <= ?", EmbeddingJobPending, time.Now()).
+			Order("next_run_at").
+			Limit(limit).
+			Find(&jobs).Error
+		if err != nil || len(jobs) == 0 {
+			return err
+		}
+		ids := make([]uint, 0, len(jobs))
+		for i := range jobs {
+			ids = append(ids, jobs[i].ID)
+			jobs[i].Attempts++
+		}
+		return tx.Model(&EmbeddingJob{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"attempts":    gorm.Expr("attempts + 1"),
+			"next_run_at": time.Now().Add(lease),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// CompleteEmbeddingJob 标记任务执行成功
+func CompleteEmbeddingJob(db *gorm.DB, id uint) error {
+	return db.Model(&EmbeddingJob{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     EmbeddingJobDone,
+		"last_error": "",
+	}).Error
+}
+
+// RetryEmbeddingJob 记录失败原因，任务在 nextRunAt 之后重试
+func RetryEmbeddingJob(db *gorm.DB, id uint, nextRunAt time.Time, lastError string) error {
+	return db.Model(&EmbeddingJob{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"next_run_at": nextRunAt,
+		"last_error":  lastError,
+	}).Error
+}
+
+// DeadEmbeddingJob 将任务移入死信状态，不再自动重试
+func DeadEmbeddingJob(db *gorm.DB, id uint, lastError string) error {
+	return db.Model(&EmbeddingJob{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     EmbeddingJobDead,
+		"last_error": lastError,
+	}).Error
+}
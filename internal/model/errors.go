@@ -0,0 +1,16 @@
+package model
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// pgUniqueViolation PostgreSQL 唯一约束冲突的错误码
+const pgUniqueViolation = "23505"
+
+// IsUniqueViolation 判断是否违反唯一约束，例如并发注册同一个用户名
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
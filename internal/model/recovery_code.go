@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RecoveryCode 两步验证的恢复码，只保存哈希，每个恢复码只能使用一次
+type RecoveryCode struct {
+	gorm.Model
+	UserID   string     `json:"user_id" gorm:"type:varchar(255);not null;index"` // 用户分布式 ID
+	CodeHash string     `json:"-" gorm:"type:varchar(255);not null"`             // 恢复码哈希，与密码使用相同的算法
+	UsedAt   *time.Time `json:"used_at"`                                         // 使用时间，未使用时为空
+}
+
+func (c *RecoveryCode) TableName() string {
+	return "totp_recovery_codes"
+}
+
+// ReplaceRecoveryCodes 删除用户原有的恢复码并写入新的恢复码
+func ReplaceRecoveryCodes(db *gorm.DB, userID string, codeHashes []string) error {
+	if err := db.Unscoped().Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	if len(codeHashes) == 0 {
+		return nil
+	}
+	codes := make([]RecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, RecoveryCode{UserID: userID, CodeHash: hash})
+	}
+	return db.Create(&codes).Error
+}
+
+// ListUnusedRecoveryCodes 获取用户尚未使用的恢复码
+func ListUnusedRecoveryCodes(db *gorm.DB, userID string) ([]RecoveryCode, error) {
+	var codes []RecoveryCode
+	err := db.Where("user_id = ? AND used_at IS NULL", userID).Find(&codes).Error
+	return codes, err
+}
+
+// UseRecoveryCode 将恢复码标记为已使用，返回 false 表示已被并发使用
+func UseRecoveryCode(db *gorm.DB, id uint) (bool, error) {
+	result := db.Model(&RecoveryCode{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
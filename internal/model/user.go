@@ -3,19 +3,42 @@ package model
 import (
 	"encoding/json"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/pgvector/pgvector-go"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 用户角色
+const (
+	RoleUser    = "user"    // 普通用户
+	RoleSupport = "support" // 客服，暂无额外权限，后台接口只对管理员开放
+	RoleAdmin   = "admin"   // 管理员
+)
+
+// 词嵌入向量的生成状态
+const (
+	EmbeddingStatusPending = "pending" // 等待后台任务生成
+	EmbeddingStatusReady   = "ready"   // 已生成
+	EmbeddingStatusFailed  = "failed"  // 多次重试后仍失败
 )
 
 type User struct {
 	gorm.Model
-	UserID        string          `json:"user_id" gorm:"type:varchar(255);not null;unique_index"` // 用户分布式 ID
-	Username      string          `json:"username" gorm:"type:varchar(255);not null;unique"`      // 用户名,唯一性，用户注册幂等性
-	Password      string          `json:"password" gorm:"type:varchar(255);not null"`             // 用户密码（加密后）
-	Like          datatypes.JSON  `json:"like" gorm:"type:jsonb;not null"`                        // 用户喜好，存储为 JSON 格式
-	LikeEmbedding pgvector.Vector `json:"like_embedding" gorm:"type:vector(768)"`                 // 喜好的词嵌入向量值
+	UserID          string           `json:"user_id" gorm:"type:varchar(255);not null;unique_index"`          // 用户分布式 ID
+	Username        string           `json:"username" gorm:"type:varchar(255);not null;unique"`               // 用户名,唯一性，用户注册幂等性
+	Password        string           `json:"password" gorm:"type:varchar(255);not null"`                      // 用户密码（加密后）
+	Like            datatypes.JSON   `json:"like" gorm:"type:jsonb;not null"`                                 // 用户喜好，存储为 JSON 格式
+	LikeEmbedding   *pgvector.Vector `json:"like_embedding" gorm:"type:vector(768)"`                          // 喜好的词嵌入向量值，尚未生成时为空
+	EmbeddingStatus string           `json:"embedding_status" gorm:"type:varchar(16);not null;default:ready"` // 词嵌入向量的生成状态
+	EmbeddingModel  string           `json:"embedding_model" gorm:"type:varchar(255)"`                        // 生成向量所用的模型
+	EmbeddingDim    int              `json:"embedding_dim"`                                                   // 向量维度
+	Role            string           `json:"role" gorm:"type:varchar(16);not null;default:user"`              // 角色：user、support、admin
+	DisabledAt      *time.Time       `json:"disabled_at"`                                                     // 被管理员禁用的时间，未禁用时为空
+	TOTPSecret      string           `json:"-" gorm:"column:totp_secret;type:varchar(255);not null"`          // 加密后的两步验证密钥，为空表示未开启
 }
 
 func (u *User) TableName() string {
@@ -37,6 +60,21 @@ func GetUserByUserID(db *gorm.DB, userID string) (*User, error) {
 	return &user, nil
 }
 
+// GetUser 按 userID 或 username 获取用户，二选一，只查询一次数据库
+func GetUser(db *gorm.DB, userID, username string) (*User, error) {
+	query := db
+	if username != "" {
+		query = query.Where("username = ?", username)
+	} else {
+		query = query.Where("user_id = ?", userID)
+	}
+	var user User
+	if err := query.First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 // GetUserIDByUsername 根据用户名获取 userID
 func GetUserIDByUsername(db *gorm.DB, username string) (string, error) {
 	var user User
@@ -47,6 +85,156 @@ func GetUserIDByUsername(db *gorm.DB, username string) (string, error) {
 	return user.UserID, nil
 }
 
+// GetUserIDsByUsernames 批量查询用户名对应的 userID，返回用户名到 userID 的映射，不存在的用户名不在结果中
+func GetUserIDsByUsernames(db *gorm.DB, usernames []string) (map[string]string, error) {
+	var users []User
+	err := db.Select("username", "user_id").Where("username IN ?", usernames).Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]string, len(users))
+	for _, user := range users {
+		ids[user.Username] = user.UserID
+	}
+	return ids, nil
+}
+
+// UpdateUser 按字段更新用户信息，updated_at 会自动更新
+func UpdateUser(db *gorm.DB, userID string, updates map[string]interface{}) error {
+	return db.Model(&User{}).Where("user_id = ?", userID).Updates(updates).Error
+}
+
+// UserFilter 后台查询用户的过滤条件，零值表示不限制
+type UserFilter struct {
+	CreatedAfter   time.Time // 注册时间下限（包含）
+	CreatedBefore  time.Time // 注册时间上限（不包含）
+	UsernamePrefix string
+	LikeContains   string // 任意一个喜好包含该文本，不区分大小写
+}
+
+// ListUsers 按过滤条件和主键游标分页查询用户，afterID 为上一页最后一个用户的主键
+func ListUsers(db *gorm.DB, filter UserFilter, afterID uint, limit int) ([]User, error) {
+	query := db.Model(&User{}).Where("id > ?", afterID)
+	if !filter.CreatedAfter.IsZero() {
+		query = query.Where("created_at >= ?", filter.CreatedAfter)
+	}
+	if !filter.CreatedBefore.IsZero() {
+		query = query.Where("created_at < ?", filter.CreatedBefore)
+	}
+	if filter.UsernamePrefix != "" {
+		query = query.Where("username LIKE ?", escapeLike(filter.UsernamePrefix)+"%")
+	}
+	if filter.LikeContains != "" {
+		query = query.Where(`EXISTS (SELECT 1 FROM jsonb_array_elements_text("like"::jsonb) AS l WHERE l ILIKE ?)`,
+			"%"+escapeLike(filter.LikeContains)+"%")
+	}
+	var users []User
+	err := query.Order("id").Limit(limit).Find(&users).Error
+	return users, err
+}
+
+// escapeLike 转义 LIKE 模式中的通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// UpdateUserEmbedding 更新用户的喜好向量、生成向量的模型和生成状态
+func UpdateUserEmbedding(db *gorm.DB, userID string, embedding *pgvector.Vector, embeddingModel string, status string) error {
+	dim := 0
+	if embedding != nil {
+		dim = len(embedding.Slice())
+	}
+	return db.Model(&User{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+		"like_embedding":   embedding,
+		"embedding_model":  embeddingModel,
+		"embedding_dim":    dim,
+		"embedding_status": status,
+	}).Error
+}
+
+// CountUsersToReembed 统计向量不是由 embeddingModel 生成、或维度不是 dim 的用户数
+func CountUsersToReembed(db *gorm.DB, embeddingModel string, dim int) (int64, error) {
+	var count int64
+	err := db.Model(&User{}).
+		Where("embedding_model IS DISTINCT FROM ? OR embedding_dim IS DISTINCT FROM ?", embeddingModel, dim).
+		Count(&count).Error
+	return count, err
+}
+
+// ListUsersToReembed 按主键顺序列出 afterID 之后需要重新生成向量的用户
+func ListUsersToReembed(db *gorm.DB, embeddingModel string, dim int, afterID uint, limit int) ([]User, error) {
+	var users []User
+	err := db.Select("id", "user_id", "\"like\"").
+		Where("embedding_model IS DISTINCT FROM ? OR embedding_dim IS DISTINCT FROM ?", embeddingModel, dim).
+		Where("id > ?", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// UpdateUserEmbeddingStatus 只更新用户的词嵌入向量生成状态
+func UpdateUserEmbeddingStatus(db *gorm.DB, userID string, status string) error {
+	return db.Model(&User{}).Where("user_id = ?", userID).Update("embedding_status", status).Error
+}
+
+// SimilarUser 相似用户查询结果，Distance 为余弦距离
+type SimilarUser struct {
+	User
+	Distance float64 `json:"distance"`
+}
+
+// FindSimilarUsers 按余弦距离查找与 embedding 最相近的用户，使用 ivfflat 向量索引
+// 只比较由同一模型 embeddingModel 生成的向量；excludeUserID 为查询者自身，与已禁用的用户一样不会出现在结果中；maxDistance <= 0 表示不限制距离
+func FindSimilarUsers(db *gorm.DB, embedding pgvector.Vector, embeddingModel string, excludeUserID string, limit int, maxDistance float64) ([]SimilarUser, error) {
+	var users []SimilarUser
+	query := db.Model(&User{}).
+		Select("*, (like_embedding <=> ?) AS distance", embedding).
+		Where("user_id <> ? AND like_embedding IS NOT NULL AND embedding_model = ? AND disabled_at IS NULL", excludeUserID, embeddingModel)
+	if maxDistance > 0 {
+		query = query.Where("(like_embedding <=> ?) <= ?", embedding, maxDistance)
+	}
+	err := query.
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "like_embedding <=> ?", Vars: []interface{}{embedding}}}).
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// DistanceCursor 向量检索的分页位置，记录上一页最后一条结果的距离和主键
+type DistanceCursor struct {
+	Distance float64 `json:"d"`
+	ID       uint    `json:"i"`
+	Query    string  `json:"q"` // 查询指纹，防止游标跨查询复用
+}
+
+// SearchUsersByEmbedding 按余弦距离由近到远分页检索由 embeddingModel 生成向量且未被禁用的用户
+// after 为 nil 表示第一页，否则返回排在 after 之后的结果，距离相同时按主键排序
+func SearchUsersByEmbedding(db *gorm.DB, embedding pgvector.Vector, embeddingModel string, limit int, after *DistanceCursor) ([]SimilarUser, error) {
+	var users []SimilarUser
+	query := db.Model(&User{}).
+		Select("*, (like_embedding <=> ?) AS distance", embedding).
+		Where("like_embedding IS NOT NULL AND embedding_model = ? AND disabled_at IS NULL", embeddingModel)
+	if after != nil {
+		query = query.Where("((like_embedding <=> ?) > ? OR ((like_embedding <=> ?) = ? AND id > ?))",
+			embedding, after.Distance, embedding, after.Distance, after.ID)
+	}
+	err := query.
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "like_embedding <=> ?, id", Vars: []interface{}{embedding}}}).
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 // 将 datatypes.JSON 转换为 []string
 func (u *User) GetLikeList() []string {
 	var likes []string
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"github.com/pgvector/pgvector-go"
+	"gorm.io/gorm"
+)
+
+// UserLike 用户的单个喜好及其词嵌入向量，用于按单个兴趣匹配用户
+type UserLike struct {
+	gorm.Model
+	UserID         string          `json:"user_id" gorm:"type:varchar(255);not null;index"` // 用户分布式 ID
+	Like           string          `json:"like" gorm:"type:text;not null"`                  // 喜好文本
+	Embedding      pgvector.Vector `json:"embedding" gorm:"type:vector(768)"`               // 喜好的词嵌入向量值
+	EmbeddingModel string          `json:"embedding_model" gorm:"type:varchar(255)"`        // 生成向量所用的模型
+}
+
+func (l *UserLike) TableName() string {
+	return "user_likes"
+}
+
+// InterestMatch 按单个兴趣匹配到的用户，记录是哪两个喜好匹配上的
+type InterestMatch struct {
+	UserID      string  `json:"user_id"`
+	Username    string  `json:"username"`
+	MyLike      string  `json:"my_like"`      // 查询方的喜好，按文本检索时为空
+	MatchedLike string  `json:"matched_like"` // 被匹配用户的喜好
+	Distance    float64 `json:"distance"`     // 余弦距离
+}
+
+// NewUserLikes 组装用户每个喜好的向量记录，embeddings 与 likes 一一对应
+func NewUserLikes(userID string, likes []string, embeddings [][]float32, embeddingModel string) []UserLike {
+	userLikes := make([]UserLike, 0, len(likes))
+	for i, like := range likes {
+		userLikes = append(userLikes, UserLike{
+			UserID:         userID,
+			Like:           like,
+			Embedding:      pgvector.NewVector(embeddings[i]),
+			EmbeddingModel: embeddingModel,
+		})
+	}
+	return userLikes
+}
+
+// CreateUserLikes 批量写入用户的喜好向量
+func CreateUserLikes(db *gorm.DB, likes []UserLike) error {
+	if len(likes) == 0 {
+		return nil
+	}
+	return db.Create(&likes).Error
+}
+
+// ReplaceUserLikes 删除用户原有的喜好向量并写入新的喜好向量
+func ReplaceUserLikes(db *gorm.DB, userID string, likes []UserLike) error {
+	if err := db.Unscoped().Where("user_id = ?", userID).Delete(&UserLike{}).Error; err != nil {
+		return err
+	}
+	return CreateUserLikes(db, likes)
+}
+
+// ListUserLikes 按主键游标分页获取用户的喜好向量，afterID 为上一页最后一条的主键
+func ListUserLikes(db *gorm.DB, userID string, afterID uint, limit int) ([]UserLike, error) {
+	var likes []UserLike
+	err := db.Where("user_id = ? AND id > ?", userID, afterID).Order("id").Limit(limit).Find(&likes).Error
+	if err != nil {
+		return nil, err
+	}
+	return likes, nil
+}
+
+// FindUsersBySharedInterest 查找与 userID 有相近喜好的用户，不包括已禁用的用户
+// 对 userID 的每个喜好通过向量索引取最近的候选，再为每个用户保留距离最近的一对喜好
+func FindUsersBySharedInterest(db *gorm.DB, userID string, limit int, maxDistance float64) ([]InterestMatch, error) {
+	var matches []InterestMatch
+	inner := db.Raw(`
+	SELECT DISTINCT ON (c.user_id) c.user_id, u.username, m."like" AS my_like, c."like" AS matched_like, c.distance
+	FROM user_likes m
+	CROSS JOIN LATERAL (
+		SELECT o.user_id, o."like", (o.embedding <=> m.embedding) AS distance
+		FROM user_likes o
+		WHERE o.user_id <> m.user_id AND o.deleted_at IS NULL AND o.embedding IS NOT NULL
+			AND o.embedding_model = m.embedding_model
+		ORDER BY o.embedding <=> m.embedding
+		LIMIT ?
+	) c
+	JOIN users u ON u.user_id = c.user_id AND u.deleted_at IS NULL AND u.disabled_at IS NULL
+	WHERE m.user_id = ? AND m.deleted_at IS NULL AND m.embedding IS NOT NULL
+	ORDER BY c.user_id, c.distance`, limit*interestCandidateFactor, userID)
+	err := nearestMatches(db, inner, limit, maxDistance).Scan(&matches).Error
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// FindUsersByLikeEmbedding 查找拥有与 embedding 相近喜好的用户，每个用户只返回最接近的一个喜好，不包括已禁用的用户
+// 只比较由同一模型 embeddingModel 生成的向量
+func FindUsersByLikeEmbedding(db *gorm.DB, embedding pgvector.Vector, embeddingModel string, excludeUserID string, limit int, maxDistance float64) ([]InterestMatch, error) {
+	var matches []InterestMatch
+	inner := db.Raw(`
+	SELECT DISTINCT ON (c.user_id) c.user_id, u.username, c."like" AS matched_like, c.distance
+	FROM (
+		SELECT o.user_id, o."like", (o.embedding <=> ?) AS distance
+		FROM user_likes o
+		WHERE o.user_id <> ? AND o.deleted_at IS NULL AND o.embedding IS NOT NULL AND o.embedding_model = ?
+		ORDER BY o.embedding <=> ?
+		LIMIT ?
+	) c
+	JOIN users u ON u.user_id = c.user_id AND u.deleted_at IS NULL AND u.disabled_at IS NULL
+	ORDER BY c.user_id, c.distance`, embedding, excludeUserID, embeddingModel, embedding, limit*interestCandidateFactor)
+	err := nearestMatches(db, inner, limit, maxDistance).Scan(&matches).Error
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// nearestMatches 对每个用户去重后的匹配结果按距离排序并截取，maxDistance <= 0 表示不限制距离
+func nearestMatches(db *gorm.DB, inner *gorm.DB, limit int, maxDistance float64) *gorm.DB {
+	query := db.Table("(?) AS t", inner)
+	if maxDistance > 0 {
+		query = query.Where("distance <= ?", maxDistance)
+	}
+	return query.Order("distance").Limit(limit)
+}
+
+// 每个喜好从向量索引中取出的候选数量相对于 limit 的倍数，
+// 同一用户的多个喜好可能同时命中，多取一些候选以保证去重后数量足够
+const interestCandidateFactor = 5
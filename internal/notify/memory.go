@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryNotifier 将通知保存在内存中，供测试读取重置凭证
+type MemoryNotifier struct {
+	mu             sync.Mutex
+	passwordResets []PasswordReset
+}
+
+func NewMemoryNotifier() *MemoryNotifier {
+	return &MemoryNotifier{}
+}
+
+func (n *MemoryNotifier) SendPasswordReset(ctx context.Context, msg PasswordReset) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.passwordResets = append(n.passwordResets, msg)
+	return nil
+}
+
+// PasswordResets 按发送顺序返回已发送的重置密码通知
+func (n *MemoryNotifier) PasswordResets() []PasswordReset {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]PasswordReset(nil), n.passwordResets...)
+}
+
+// LastPasswordReset 返回最近一次发送给 username 的重置密码通知
+func (n *MemoryNotifier) LastPasswordReset(username string) (PasswordReset, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for i := len(n.passwordResets) - 1; i >= 0; i-- {
+		if n.passwordResets[i].Username == username {
+			return n.passwordResets[i], true
+		}
+	}
+	return PasswordReset{}, false
+}
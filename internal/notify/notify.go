@@ -0,0 +1,110 @@
+package notify
+
+// 向用户发送通知，例如重置密码的凭证
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/HCH1212/taxin/config"
+)
+
+// PasswordReset 重置密码通知
+type PasswordReset struct {
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Notifier 通知发送方式，本地开发可以使用日志或文件，测试使用内存，线上通过 webhook 交给邮件、短信等投递服务
+type Notifier interface {
+	SendPasswordReset(ctx context.Context, msg PasswordReset) error
+}
+
+// Default 全局使用的通知发送方式，由 InitNotifier 根据配置创建
+var Default Notifier = LogNotifier{}
+
+// InitNotifier 根据配置初始化全局通知发送方式
+func InitNotifier() {
+	conf := config.GetConf()
+	// 日志、文件和内存都不是真正的投递方式，文件还会以明文保存可用的重置凭证，线上必须把通知交给投递服务
+	if conf.Env == "online" && conf.Notifier.Type != "webhook" {
+		log.Fatalf("notifier type %q is not allowed in online, configure a webhook delivery sink", conf.Notifier.Type)
+	}
+	notifier, err := NewNotifier(conf.Notifier)
+	if err != nil {
+		log.Fatal(err)
+	}
+	Default = notifier
+}
+
+// NewNotifier 根据 type 创建对应的通知发送方式
+func NewNotifier(conf config.Notifier) (Notifier, error) {
+	switch conf.Type {
+	case "", "log":
+		return LogNotifier{}, nil
+	case "file":
+		if conf.FilePath == "" {
+			return nil, fmt.Errorf("notifier file_path is required")
+		}
+		return NewFileNotifier(conf.FilePath), nil
+	case "memory":
+		return NewMemoryNotifier(), nil
+	case "webhook":
+		if conf.URL == "" {
+			return nil, fmt.Errorf("notifier url is required")
+		}
+		return NewWebhookNotifier(conf.URL, conf.Secret, conf.Timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown notifier type: %s", conf.Type)
+	}
+}
+
+// LogNotifier 将通知输出到日志，不会输出重置凭证，只用于本地开发
+type LogNotifier struct{}
+
+func (LogNotifier) SendPasswordReset(ctx context.Context, msg PasswordReset) error {
+	log.Printf("password reset for user %s (%s) requested, expires at %s",
+		msg.Username, msg.UserID, msg.ExpiresAt.Format(time.RFC3339))
+	return nil
+}
+
+// FileNotifier 将通知以 JSON Lines 格式追加到文件
+type FileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{path: path}
+}
+
+func (n *FileNotifier) SendPasswordReset(ctx context.Context, msg PasswordReset) error {
+	return n.append(map[string]interface{}{
+		"type":    "password_reset",
+		"payload": msg,
+	})
+}
+
+// append 追加一行记录
+func (n *FileNotifier) append(record interface{}) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	file, err := os.OpenFile(n.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,74 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/HCH1212/taxin/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNotifier(t *testing.T) {
+	_, err := NewNotifier(config.Notifier{Type: "file"})
+	assert.Error(t, err)
+	_, err = NewNotifier(config.Notifier{Type: "webhook"})
+	assert.Error(t, err)
+	_, err = NewNotifier(config.Notifier{Type: "sms"})
+	assert.Error(t, err)
+
+	notifier, err := NewNotifier(config.Notifier{Type: "memory"})
+	require.NoError(t, err)
+	assert.IsType(t, &MemoryNotifier{}, notifier)
+}
+
+func TestMemoryNotifier(t *testing.T) {
+	n := NewMemoryNotifier()
+	ctx := context.Background()
+	require.NoError(t, n.SendPasswordReset(ctx, PasswordReset{Username: "alice", Token: "first"}))
+	require.NoError(t, n.SendPasswordReset(ctx, PasswordReset{Username: "bob", Token: "other"}))
+	require.NoError(t, n.SendPasswordReset(ctx, PasswordReset{Username: "alice", Token: "second"}))
+
+	msg, ok := n.LastPasswordReset("alice")
+	assert.True(t, ok)
+	assert.Equal(t, "second", msg.Token)
+	_, ok = n.LastPasswordReset("carol")
+	assert.False(t, ok)
+	assert.Len(t, n.PasswordResets(), 3)
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var received struct {
+		Type    string        `json:"type"`
+		Payload PasswordReset `json:"payload"`
+	}
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(server.URL, "secret", time.Second)
+	err := n.SendPasswordReset(context.Background(), PasswordReset{UserID: "user-1", Username: "alice", Token: "token"})
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer secret", authorization)
+	assert.Equal(t, "password_reset", received.Type)
+	assert.Equal(t, "token", received.Payload.Token)
+}
+
+func TestWebhookNotifierError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(server.URL, "", time.Second)
+	assert.Error(t, n.SendPasswordReset(context.Background(), PasswordReset{Username: "alice"}))
+}
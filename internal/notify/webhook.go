@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// 未配置时请求投递服务的默认超时时间
+const defaultWebhookTimeout = 5 * time.Second
+
+// WebhookNotifier 将通知以 JSON 格式 POST 给投递服务，由投递服务发送邮件或短信
+type WebhookNotifier struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewWebhookNotifier 创建 webhook 通知，secret 不为空时放在 Authorization 头中，timeout <= 0 时使用默认超时时间
+func NewWebhookNotifier(url, secret string, timeout time.Duration) *WebhookNotifier {
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+	return &WebhookNotifier{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (n *WebhookNotifier) SendPasswordReset(ctx context.Context, msg PasswordReset) error {
+	return n.post(ctx, map[string]interface{}{
+		"type":    "password_reset",
+		"payload": msg,
+	})
+}
+
+// post 发送一条通知，投递服务返回非 2xx 时视为失败
+func (n *WebhookNotifier) post(ctx context.Context, record interface{}) error {
+	body, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.secret != "" {
+		req.Header.Set("Authorization", "Bearer "+n.secret)
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notifier webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pb "github.com/HCH1212/taxin/api/pb/user"
+	"github.com/HCH1212/taxin/config"
+	"github.com/HCH1212/taxin/internal/dao"
+	"github.com/HCH1212/taxin/internal/model"
+	"github.com/HCH1212/taxin/internal/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+// errRestoreNotAllowed 账号不存在、未注销、密码错误或已超过宽限期
+var errRestoreNotAllowed = errors.New("account cannot be restored")
+
+// DeleteAccount 注销当前账号：软删除用户、使所有 token 失效并清除注册幂等键，宽限期后由后台任务彻底删除
+func (u *UserService) DeleteAccount(ctx context.Context, req *pb.DeleteAccountReq) (*pb.DeleteAccountResp, error) {
+	tr := otel.Tracer("user-service")
+	_, span := tr.Start(ctx, "DeleteAccount")
+	defer span.End()
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "missing user ID in context")
+		return nil, errors.New("missing user ID in context")
+	}
+	span.SetAttributes(attribute.String("user_id", userID))
+	// 参数校验
+	if req.Password == "" {
+		span.SetStatus(codes.Error, "invalid request")
+		return nil, errors.New("invalid request")
+	}
+	user, err := model.GetUserByUserID(dao.DB, userID)
+	if err != nil {
+		span.SetStatus(codes.Error, "user not found")
+		return nil, err
+	}
+	if !utils.VerifyPassword(user.Password, req.Password) {
+		span.SetStatus(codes.Error, "invalid password")
+		return nil, errors.New("invalid password")
+	}
+	if err := model.SoftDeleteUser(dao.DB, userID); err != nil {
+		span.SetStatus(codes.Error, "delete user failed")
+		return nil, err
+	}
+	if err := revokeUserTokens(ctx, userID); err != nil {
+		span.SetStatus(codes.Error, "revoke tokens failed")
+		return nil, err
+	}
+	// 清除注册幂等键，避免再次注册同名用户时返回已注销的 user_id
+	if user.Username != "" {
+		if err := dao.RedisClient.Del(ctx, registerRedisKey(user.Username)).Err(); err != nil {
+			span.SetStatus(codes.Error, "redis error")
+			return nil, err
+		}
+	}
+	purgeAt := time.Now().Add(deleteGracePeriod())
+	recordAuditEvent(ctx, userID, model.AuditEventAccountDeleted, clientIP(ctx), map[string]interface{}{
+		"purge_at": purgeAt.Format(time.RFC3339),
+	})
+	span.AddEvent("delete account success")
+	return &pb.DeleteAccountResp{PurgeAt: purgeAt.Format("2006-01-02 15:04:05")}, nil
+}
+
+// RestoreAccount 在宽限期内恢复已注销的账号，恢复后需要重新登录
+func (u *UserService) RestoreAccount(ctx context.Context, req *pb.RestoreAccountReq) (*pb.RestoreAccountResp, error) {
+	tr := otel.Tracer("user-service")
+	_, span := tr.Start(ctx, "RestoreAccount")
+	defer span.End()
+	// 参数校验，user_id 和 username 只能提供一个
+	if (req.UserId == "") == (req.Username == "") || req.Password == "" {
+		span.SetStatus(codes.Error, "invalid request")
+		return nil, errors.New("invalid request")
+	}
+	user, err := model.GetDeletedUser(dao.DB, req.UserId, req.Username)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		span.SetStatus(codes.Error, "query user failed")
+		return nil, err
+	}
+	var userID string
+	if user != nil {
+		userID = user.UserID
+		span.SetAttributes(attribute.String("user_id", userID))
+	}
+	// 与登录共用防暴力破解限制
+	account := loginAccountKey(user, req.UserId, req.Username)
+	ip := clientIP(ctx)
+	if err := checkLoginAllowed(ctx, account, ip); err != nil {
+		span.SetStatus(codes.Error, "restore rejected by rate limit")
+		return nil, err
+	}
+	passwordHash := dummyPasswordHash()
+	if user != nil {
+		passwordHash = user.Password
+	}
+	if !utils.VerifyPassword(passwordHash, req.Password) || user == nil {
+		if err := recordLoginFailure(ctx, span, account, userID, ip); err != nil {
+			span.SetStatus(codes.Error, "record login failure failed")
+			return nil, err
+		}
+		span.SetStatus(codes.Error, "invalid credentials")
+		return nil, errRestoreNotAllowed
+	}
+	if err := dao.ClearLoginFailures(ctx, account); err != nil {
+		span.SetStatus(codes.Error, "redis error")
+		return nil, err
+	}
+	restored, err := model.RestoreUser(dao.DB, userID, time.Now().Add(-deleteGracePeriod()))
+	if err != nil {
+		span.SetStatus(codes.Error, "restore user failed")
+		return nil, err
+	}
+	if !restored {
+		span.SetStatus(codes.Error, "grace period expired")
+		return nil, errRestoreNotAllowed
+	}
+	// 注销期间后台任务不会处理该用户，向量未生成时重新排队
+	if user.EmbeddingStatus == model.EmbeddingStatusPending {
+		if err := model.CreateEmbeddingJob(dao.DB, userID); err != nil {
+			span.SetStatus(codes.Error, "create embedding job failed")
+			return nil, err
+		}
+	}
+	recordAuditEvent(ctx, userID, model.AuditEventAccountRestored, ip, nil)
+	span.AddEvent("restore account success")
+	return &pb.RestoreAccountResp{}, nil
+}
+
+// deleteGracePeriod 注销后可以恢复的时间
+func deleteGracePeriod() time.Duration {
+	if period := config.GetConf().Account.DeleteGracePeriod; period > 0 {
+		return period
+	}
+	return model.DefaultDeleteGracePeriod
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/HCH1212/taxin/api/pb/user"
+	"github.com/HCH1212/taxin/internal/dao"
+	"github.com/HCH1212/taxin/internal/model"
+	"github.com/HCH1212/taxin/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// deleteTestUser 通过 DeleteAccount 注销用户
+func deleteTestUser(t *testing.T, userID, password string) {
+	t.Helper()
+	ctx := context.WithValue(context.Background(), "user_id", userID)
+	_, err := (&UserService{}).DeleteAccount(ctx, &pb.DeleteAccountReq{Password: password})
+	require.NoError(t, err)
+}
+
+// backdateDeletion 将注销时间提前，模拟已经过去了一段时间
+func backdateDeletion(t *testing.T, userID string, ago time.Duration) {
+	t.Helper()
+	err := dao.DB.Unscoped().Model(&model.User{}).Where("user_id = ?", userID).
+		Update("deleted_at", time.Now().Add(-ago)).Error
+	require.NoError(t, err)
+}
+
+func TestRestoreAccountWithinGracePeriod(t *testing.T) {
+	setupTestRedis(t)
+	setupTestDB(t)
+	createTestUser(t, "user-restore", "grace", "secret")
+	deleteTestUser(t, "user-restore", "secret")
+	ctx := context.Background()
+	u := &UserService{}
+
+	_, err := model.GetUserByUserID(dao.DB, "user-restore")
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	// 宽限期即将结束时仍可以恢复，密码错误时不能恢复
+	backdateDeletion(t, "user-restore", deleteGracePeriod()-time.Hour)
+	_, err = u.RestoreAccount(ctx, &pb.RestoreAccountReq{Username: "grace", Password: "wrong"})
+	assert.Equal(t, errRestoreNotAllowed, err)
+	_, err = u.RestoreAccount(ctx, &pb.RestoreAccountReq{Username: "grace", Password: "secret"})
+	require.NoError(t, err)
+
+	user, err := model.GetUserByUserID(dao.DB, "user-restore")
+	require.NoError(t, err)
+	assert.False(t, user.DeletedAt.Valid)
+	// 恢复后可以重新登录
+	_, err = u.Login(ctx, &pb.LoginReq{Username: "grace", Password: "secret"})
+	assert.NoError(t, err)
+}
+
+func TestRestoreAccountAfterGracePeriod(t *testing.T) {
+	setupTestRedis(t)
+	setupTestDB(t)
+	createTestUser(t, "user-expired", "expired", "secret")
+	deleteTestUser(t, "user-expired", "secret")
+	backdateDeletion(t, "user-expired", deleteGracePeriod()+time.Hour)
+
+	_, err := (&UserService{}).RestoreAccount(context.Background(),
+		&pb.RestoreAccountReq{UserId: "user-expired", Password: "secret"})
+	assert.Equal(t, errRestoreNotAllowed, err)
+	_, err = model.GetUserByUserID(dao.DB, "user-expired")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	// 超过宽限期的用户由后台任务彻底删除
+	userIDs, err := model.ListUsersToPurge(dao.DB, time.Now().Add(-deleteGracePeriod()), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user-expired"}, userIDs)
+}
+
+func TestPurgeUser(t *testing.T) {
+	setupTestRedis(t)
+	setupTestDB(t)
+	createTestUser(t, "user-purge", "purged", "secret")
+	embedding := make([]float32, utils.EmbeddingDimension)
+	embedding[0] = 1
+	likes := model.NewUserLikes("user-purge", []string{"reading"}, [][]float32{embedding}, "hash")
+	require.NoError(t, model.CreateUserLikes(dao.DB, likes))
+	require.NoError(t, model.CreateEmbeddingJob(dao.DB, "user-purge"))
+	require.NoError(t, dao.DB.Create(&model.RecoveryCode{UserID: "user-purge", CodeHash: "hash"}).Error)
+
+	// 未注销的用户不能被彻底删除
+	assert.ErrorIs(t, model.PurgeUser(dao.DB, "user-purge"), gorm.ErrRecordNotFound)
+
+	deleteTestUser(t, "user-purge", "secret")
+	// user_likes 和恢复码引用 users，顺序错误时会违反外键约束
+	require.NoError(t, model.PurgeUser(dao.DB, "user-purge"))
+
+	for _, m := range []interface{}{&model.User{}, &model.UserLike{}, &model.EmbeddingJob{}, &model.RecoveryCode{}} {
+		var count int64
+		require.NoError(t, dao.DB.Unscoped().Model(m).Where("user_id = ?", "user-purge").Count(&count).Error)
+		assert.Zero(t, count, "%T", m)
+	}
+	// 审计日志保留，但不再关联到该用户
+	var events []model.AuditEvent
+	require.NoError(t, dao.DB.Where("event = ?", model.AuditEventAccountDeleted).Find(&events).Error)
+	require.Len(t, events, 1)
+	assert.Empty(t, events[0].UserID)
+	var linked int64
+	require.NoError(t, dao.DB.Model(&model.AuditEvent{}).Where("user_id = ?", "user-purge").Count(&linked).Error)
+	assert.Zero(t, linked)
+}
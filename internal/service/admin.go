@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	pb "github.com/HCH1212/taxin/api/pb/admin"
+	"github.com/HCH1212/taxin/internal/dao"
+	"github.com/HCH1212/taxin/internal/model"
+	"github.com/HCH1212/taxin/internal/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+type AdminService struct {
+	pb.UnimplementedAdminServiceServer
+}
+
+// userListCursor ListUsers 的分页位置
+type userListCursor struct {
+	ID uint `json:"id"`
+}
+
+// ListUsers 按注册时间、用户名前缀和喜好过滤用户，按主键游标分页
+func (a *AdminService) ListUsers(ctx context.Context, req *pb.ListUsersReq) (*pb.ListUsersResp, error) {
+	tr := otel.Tracer("admin-service")
+	_, span := tr.Start(ctx, "ListUsers")
+	defer span.End()
+	operatorID, _ := userIDFromContext(ctx)
+	span.SetAttributes(attribute.String("operator_id", operatorID))
+
+	var cursor userListCursor
+	if req.Cursor != "" {
+		if err := utils.DecodeCursor(req.Cursor, &cursor); err != nil {
+			span.SetStatus(codes.Error, "invalid cursor")
+			return nil, err
+		}
+	}
+	filter := model.UserFilter{
+		UsernamePrefix: req.UsernamePrefix,
+		LikeContains:   req.LikeContains,
+	}
+	if req.CreatedAfter != nil {
+		filter.CreatedAfter = req.CreatedAfter.AsTime()
+	}
+	if req.CreatedBefore != nil {
+		filter.CreatedBefore = req.CreatedBefore.AsTime()
+	}
+	pageSize := clampLimit(req.PageSize, defaultPageSize, maxPageSize)
+	// 多查一条用于判断是否还有下一页
+	users, err := model.ListUsers(dao.DB, filter, cursor.ID, pageSize+1)
+	if err != nil {
+		span.SetStatus(codes.Error, "list users failed")
+		return nil, err
+	}
+	resp := &pb.ListUsersResp{}
+	if len(users) > pageSize {
+		users = users[:pageSize]
+		next, err := utils.EncodeCursor(userListCursor{ID: users[len(users)-1].ID})
+		if err != nil {
+			span.SetStatus(codes.Error, "encode cursor failed")
+			return nil, err
+		}
+		resp.NextCursor = next
+	}
+	resp.Users = make([]*pb.AdminUser, 0, len(users))
+	for i := range users {
+		resp.Users = append(resp.Users, toAdminUser(&users[i]))
+	}
+	span.SetAttributes(attribute.Int("result_count", len(resp.Users)))
+	return resp, nil
+}
+
+// GetUser 按 user_id 或 username 查询用户
+func (a *AdminService) GetUser(ctx context.Context, req *pb.GetUserReq) (*pb.AdminUser, error) {
+	tr := otel.Tracer("admin-service")
+	_, span := tr.Start(ctx, "GetUser")
+	defer span.End()
+	operatorID, _ := userIDFromContext(ctx)
+	span.SetAttributes(attribute.String("operator_id", operatorID))
+
+	userID := req.GetUserId()
+	if username := req.GetUsername(); username != "" {
+		var err error
+		if userID, err = model.GetUserIDByUsername(dao.DB, username); err != nil {
+			span.SetStatus(codes.Error, "user not found")
+			return nil, err
+		}
+	}
+	if userID == "" {
+		span.SetStatus(codes.Error, "invalid request")
+		return nil, errors.New("invalid request")
+	}
+	span.SetAttributes(attribute.String("user_id", userID))
+	user, err := model.GetUserByUserID(dao.DB, userID)
+	if err != nil {
+		span.SetStatus(codes.Error, "user not found")
+		return nil, err
+	}
+	return toAdminUser(user), nil
+}
+
+// DisableUser 禁用用户，禁用后无法登录，已签发的 token 全部失效
+func (a *AdminService) DisableUser(ctx context.Context, req *pb.DisableUserReq) (*pb.DisableUserResp, error) {
+	tr := otel.Tracer("admin-service")
+	_, span := tr.Start(ctx, "DisableUser")
+	defer span.End()
+	operatorID, _ := userIDFromContext(ctx)
+	span.SetAttributes(attribute.String("operator_id", operatorID), attribute.String("user_id", req.UserId))
+	if req.UserId == "" {
+		span.SetStatus(codes.Error, "invalid request")
+		return nil, errors.New("invalid request")
+	}
+	if req.UserId == operatorID {
+		span.SetStatus(codes.Error, "cannot disable yourself")
+		return nil, errors.New("cannot disable yourself")
+	}
+	if err := setUserDisabled(req.UserId, true); err != nil {
+		span.SetStatus(codes.Error, "disable user failed")
+		return nil, err
+	}
+	if err := revokeUserTokens(ctx, req.UserId); err != nil {
+		span.SetStatus(codes.Error, "revoke tokens failed")
+		return nil, err
+	}
+	recordAuditEvent(ctx, req.UserId, model.AuditEventUserDisabled, clientIP(ctx), map[string]interface{}{
+		"operator_id": operatorID,
+		"reason":      req.Reason,
+	})
+	span.AddEvent("disable user success")
+	return &pb.DisableUserResp{}, nil
+}
+
+// EnableUser 解除禁用
+func (a *AdminService) EnableUser(ctx context.Context, req *pb.EnableUserReq) (*pb.EnableUserResp, error) {
+	tr := otel.Tracer("admin-service")
+	_, span := tr.Start(ctx, "EnableUser")
+	defer span.End()
+	operatorID, _ := userIDFromContext(ctx)
+	span.SetAttributes(attribute.String("operator_id", operatorID), attribute.String("user_id", req.UserId))
+	if req.UserId == "" {
+		span.SetStatus(codes.Error, "invalid request")
+		return nil, errors.New("invalid request")
+	}
+	if err := setUserDisabled(req.UserId, false); err != nil {
+		span.SetStatus(codes.Error, "enable user failed")
+		return nil, err
+	}
+	recordAuditEvent(ctx, req.UserId, model.AuditEventUserEnabled, clientIP(ctx), map[string]interface{}{
+		"operator_id": operatorID,
+	})
+	span.AddEvent("enable user success")
+	return &pb.EnableUserResp{}, nil
+}
+
+// ForceLogout 强制用户退出所有登录
+func (a *AdminService) ForceLogout(ctx context.Context, req *pb.ForceLogoutReq) (*pb.ForceLogoutResp, error) {
+	tr := otel.Tracer("admin-service")
+	_, span := tr.Start(ctx, "ForceLogout")
+	defer span.End()
+	operatorID, _ := userIDFromContext(ctx)
+	span.SetAttributes(attribute.String("operator_id", operatorID), attribute.String("user_id", req.UserId))
+	if req.UserId == "" {
+		span.SetStatus(codes.Error, "invalid request")
+		return nil, errors.New("invalid request")
+	}
+	// 确认用户存在
+	if _, err := model.GetUserByUserID(dao.DB, req.UserId); err != nil {
+		span.SetStatus(codes.Error, "user not found")
+		return nil, err
+	}
+	if err := revokeUserTokens(ctx, req.UserId); err != nil {
+		span.SetStatus(codes.Error, "revoke tokens failed")
+		return nil, err
+	}
+	recordAuditEvent(ctx, req.UserId, model.AuditEventForceLogout, clientIP(ctx), map[string]interface{}{
+		"operator_id": operatorID,
+	})
+	span.AddEvent("force logout success")
+	return &pb.ForceLogoutResp{}, nil
+}
+
+// ImportUsers 批量导入用户，按批生成向量并写入，每处理完一批立即返回该批每一行的结果和累计数量
+func (a *AdminService) ImportUsers(stream pb.AdminService_ImportUsersServer) error {
+	ctx := stream.Context()
+	tr := otel.Tracer("admin-service")
+	_, span := tr.Start(ctx, "ImportUsers")
+	defer span.End()
+	operatorID, _ := userIDFromContext(ctx)
+	span.SetAttributes(attribute.String("operator_id", operatorID))
+
+	importer := NewUserImporter(0)
+	var created, existing, failed int32
+	// 已写入的行无论后续是否出错都记录审计日志
+	defer func() {
+		span.SetAttributes(
+			attribute.Int("created", int(created)),
+			attribute.Int("existing", int(existing)),
+			attribute.Int("failed", int(failed)),
+		)
+		recordAuditEvent(ctx, operatorID, model.AuditEventUsersImported, clientIP(ctx), map[string]interface{}{
+			"created":  created,
+			"existing": existing,
+			"failed":   failed,
+		})
+	}()
+	send := func(results []ImportResult) error {
+		if len(results) == 0 {
+			return nil
+		}
+		resp := &pb.ImportUsersResp{Results: make([]*pb.ImportUserResult, 0, len(results))}
+		for _, r := range results {
+			switch r.Status {
+			case ImportStatusCreated:
+				created++
+			case ImportStatusExists:
+				existing++
+			default:
+				failed++
+			}
+			resp.Results = append(resp.Results, &pb.ImportUserResult{
+				Row:             int32(r.Row),
+				Username:        r.Username,
+				UserId:          r.UserID,
+				Status:          r.Status,
+				EmbeddingStatus: r.EmbeddingStatus,
+				Error:           r.Error,
+			})
+		}
+		resp.Created, resp.Existing, resp.Failed = created, existing, failed
+		return stream.Send(resp)
+	}
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			span.SetStatus(codes.Error, "receive row failed")
+			// 尚未处理的行不会写入，客户端只会收到已写入的行的结果
+			return err
+		}
+		results := importer.Add(ctx, ImportRow{
+			Username:     req.Username,
+			Password:     req.Password,
+			PasswordHash: req.PasswordHash,
+			Like:         req.Like,
+		})
+		if err := send(results); err != nil {
+			span.SetStatus(codes.Error, "send results failed")
+			return err
+		}
+	}
+	if err := send(importer.Flush(ctx)); err != nil {
+		span.SetStatus(codes.Error, "send results failed")
+		return err
+	}
+	span.AddEvent("import users success")
+	return nil
+}
+
+// ExportUsers 按条件导出用户，JSONL 边生成边分块发送
+func (a *AdminService) ExportUsers(req *pb.ExportUsersReq, stream pb.AdminService_ExportUsersServer) error {
+	ctx := stream.Context()
+	tr := otel.Tracer("admin-service")
+	_, span := tr.Start(ctx, "ExportUsers")
+	defer span.End()
+	operatorID, _ := userIDFromContext(ctx)
+	span.SetAttributes(
+		attribute.String("operator_id", operatorID),
+		attribute.Bool("include_password_hash", req.IncludePasswordHash),
+	)
+
+	filter := model.UserFilter{
+		UsernamePrefix: req.UsernamePrefix,
+		LikeContains:   req.LikeContains,
+	}
+	if req.CreatedAfter != nil {
+		filter.CreatedAfter = req.CreatedAfter.AsTime()
+	}
+	if req.CreatedBefore != nil {
+		filter.CreatedBefore = req.CreatedBefore.AsTime()
+	}
+	var count int
+	err := pipeChunks(func(w io.Writer) error {
+		var err error
+		count, err = WriteUsersJSONL(ctx, w, filter, req.IncludePasswordHash)
+		return err
+	}, func(chunk []byte) error {
+		return stream.Send(&pb.ExportUsersResp{Content: chunk})
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, "export users failed")
+		return err
+	}
+	span.SetAttributes(attribute.Int("result_count", count))
+	recordAuditEvent(ctx, operatorID, model.AuditEventUsersExported, clientIP(ctx), map[string]interface{}{
+		"count":                 count,
+		"include_password_hash": req.IncludePasswordHash,
+	})
+	span.AddEvent("export users success")
+	return nil
+}
+
+// setUserDisabled 设置或清除用户的禁用时间，用户不存在时返回 gorm.ErrRecordNotFound
+func setUserDisabled(userID string, disabled bool) error {
+	var disabledAt interface{}
+	if disabled {
+		disabledAt = time.Now()
+	}
+	result := dao.DB.Model(&model.User{}).Where("user_id = ?", userID).Update("disabled_at", disabledAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// toAdminUser 将用户转换为后台展示的结构，不包含密码和密钥
+func toAdminUser(user *model.User) *pb.AdminUser {
+	resp := &pb.AdminUser{
+		UserId:          user.UserID,
+		Username:        user.Username,
+		Like:            user.GetLikeList(),
+		Role:            user.Role,
+		Disabled:        user.DisabledAt != nil,
+		TotpEnabled:     user.TOTPSecret != "",
+		EmbeddingStatus: user.EmbeddingStatus,
+		CreateAt:        user.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdateAt:        user.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if user.DisabledAt != nil {
+		resp.DisabledAt = user.DisabledAt.Format("2006-01-02 15:04:05")
+	}
+	return resp
+}
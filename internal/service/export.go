@@ -0,0 +1,314 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"time"
+
+	pb "github.com/HCH1212/taxin/api/pb/user"
+	"github.com/HCH1212/taxin/internal/dao"
+	"github.com/HCH1212/taxin/internal/model"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	exportFileName     = "data.json" // zip 压缩包中导出文件的名称
+	exportDataPageSize = 500         // 导出个人数据时每次查询的喜好和审计日志条数
+)
+
+// 导出文件的内容依次为 exported_at、profile、likes、embedding、sessions、audit_events，
+// 不包含密码、两步验证密钥和向量值。喜好和审计日志按主键分页查询，每页查到后立即写出
+
+type exportProfile struct {
+	UserID      string     `json:"user_id"`
+	Username    string     `json:"username"`
+	Like        []string   `json:"like"`
+	Role        string     `json:"role"`
+	TOTPEnabled bool       `json:"totp_enabled"`
+	DisabledAt  *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type exportLike struct {
+	Like           string    `json:"like"`
+	EmbeddingModel string    `json:"embedding_model"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type exportEmbedding struct {
+	Status string `json:"status"`
+	Model  string `json:"model"`
+	Dim    int    `json:"dim"`
+}
+
+type exportSession struct {
+	SessionID  string    `json:"session_id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+type exportAuditEvent struct {
+	Event     string          `json:"event"`
+	IP        string          `json:"ip"`
+	Detail    json.RawMessage `json:"detail,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// ExportMyData 导出与当前用户有关的全部数据，边生成边分块发送，不会把整个文件加载到内存
+func (u *UserService) ExportMyData(req *pb.ExportMyDataReq, stream pb.UserService_ExportMyDataServer) error {
+	ctx := stream.Context()
+	tr := otel.Tracer("user-service")
+	_, span := tr.Start(ctx, "ExportMyData")
+	defer span.End()
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "missing user ID in context")
+		return errors.New("missing user ID in context")
+	}
+	span.SetAttributes(attribute.String("user_id", userID), attribute.Bool("zip", req.Zip))
+
+	err := pipeChunks(func(w io.Writer) error {
+		return writeExport(ctx, w, userID, req.Zip)
+	}, func(chunk []byte) error {
+		return stream.Send(&pb.ExportMyDataResp{Content: chunk})
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, "export data failed")
+		return err
+	}
+	recordAuditEvent(ctx, userID, model.AuditEventDataExported, clientIP(ctx), map[string]interface{}{
+		"zip": req.Zip,
+	})
+	span.AddEvent("export data success")
+	return nil
+}
+
+// writeExport 查询用户数据并以 JSON（或包含 JSON 的 zip）写入 w
+func writeExport(ctx context.Context, w io.Writer, userID string, zipped bool) error {
+	if !zipped {
+		return encodeExport(ctx, w, userID)
+	}
+	zw := zip.NewWriter(w)
+	f, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     exportFileName,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := encodeExport(ctx, f, userID); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// encodeExport 依次查询用户资料、喜好、向量元数据、会话和审计日志，边查询边写入 w
+func encodeExport(ctx context.Context, w io.Writer, userID string) error {
+	db := dao.DB.WithContext(ctx)
+	user, err := model.GetUserByUserID(db, userID)
+	if err != nil {
+		return err
+	}
+	enc := &jsonObjectEncoder{w: w}
+	if err := enc.field("exported_at", time.Now()); err != nil {
+		return err
+	}
+	err = enc.field("profile", exportProfile{
+		UserID:      user.UserID,
+		Username:    user.Username,
+		Like:        user.GetLikeList(),
+		Role:        user.Role,
+		TOTPEnabled: user.TOTPSecret != "",
+		DisabledAt:  user.DisabledAt,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := enc.beginArray("likes"); err != nil {
+		return err
+	}
+	var afterID uint
+	for {
+		likes, err := model.ListUserLikes(db, userID, afterID, exportDataPageSize)
+		if err != nil {
+			return err
+		}
+		for _, l := range likes {
+			err := enc.element(exportLike{
+				Like:           l.Like,
+				EmbeddingModel: l.EmbeddingModel,
+				CreatedAt:      l.CreatedAt,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if len(likes) < exportDataPageSize {
+			break
+		}
+		afterID = likes[len(likes)-1].ID
+	}
+	if err := enc.endArray(); err != nil {
+		return err
+	}
+
+	err = enc.field("embedding", exportEmbedding{
+		Status: user.EmbeddingStatus,
+		Model:  user.EmbeddingModel,
+		Dim:    user.EmbeddingDim,
+	})
+	if err != nil {
+		return err
+	}
+
+	// 会话只包含未过期的，数量有限，一次取出
+	sessions, err := dao.ListSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+	if err := enc.beginArray("sessions"); err != nil {
+		return err
+	}
+	for _, s := range sessions {
+		err := enc.element(exportSession{
+			SessionID:  s.ID,
+			UserAgent:  s.UserAgent,
+			IP:         s.IP,
+			CreatedAt:  s.CreatedAt,
+			LastSeenAt: s.LastSeenAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err := enc.endArray(); err != nil {
+		return err
+	}
+
+	if err := enc.beginArray("audit_events"); err != nil {
+		return err
+	}
+	afterID = 0
+	for {
+		events, err := model.ListAuditEvents(db, userID, afterID, exportDataPageSize)
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			event := exportAuditEvent{
+				Event:     e.Event,
+				IP:        e.IP,
+				CreatedAt: e.CreatedAt,
+			}
+			if len(e.Detail) > 0 && string(e.Detail) != "null" {
+				event.Detail = json.RawMessage(e.Detail)
+			}
+			if err := enc.element(event); err != nil {
+				return err
+			}
+		}
+		if len(events) < exportDataPageSize {
+			break
+		}
+		afterID = events[len(events)-1].ID
+	}
+	if err := enc.endArray(); err != nil {
+		return err
+	}
+	return enc.close()
+}
+
+// jsonObjectEncoder 逐个字段写出带缩进的 JSON 对象，数组字段逐个元素写出，不需要把整个数组放在内存中
+type jsonObjectEncoder struct {
+	w      io.Writer
+	fields int // 已写出的字段数
+	items  int // 当前数组已写出的元素数
+}
+
+// field 写出一个字段
+func (e *jsonObjectEncoder) field(name string, v interface{}) error {
+	if err := e.key(name); err != nil {
+		return err
+	}
+	return e.value("  ", v)
+}
+
+// beginArray 开始写出一个数组字段，之后调用 element 写出元素，最后调用 endArray
+func (e *jsonObjectEncoder) beginArray(name string) error {
+	if err := e.key(name); err != nil {
+		return err
+	}
+	e.items = 0
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonObjectEncoder) element(v interface{}) error {
+	sep := "\n    "
+	if e.items > 0 {
+		sep = ",\n    "
+	}
+	e.items++
+	if _, err := io.WriteString(e.w, sep); err != nil {
+		return err
+	}
+	return e.value("    ", v)
+}
+
+func (e *jsonObjectEncoder) endArray() error {
+	end := "]"
+	if e.items > 0 {
+		end = "\n  ]"
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
+
+// close 结束对象
+func (e *jsonObjectEncoder) close() error {
+	end := "\n}\n"
+	if e.fields == 0 {
+		end = "{}\n"
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
+
+func (e *jsonObjectEncoder) key(name string) error {
+	sep := "{\n  "
+	if e.fields > 0 {
+		sep = ",\n  "
+	}
+	e.fields++
+	key, err := json.Marshal(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(e.w, sep+string(key)+": ")
+	return err
+}
+
+func (e *jsonObjectEncoder) value(prefix string, v interface{}) error {
+	data, err := json.MarshalIndent(v, prefix, "  ")
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
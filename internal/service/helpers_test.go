@@ -0,0 +1,122 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HCH1212/taxin/config"
+	"github.com/HCH1212/taxin/internal/dao"
+	"github.com/HCH1212/taxin/internal/notify"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupTestRedis 使用 miniredis 替换全局 Redis 客户端，测试结束后恢复
+func setupTestRedis(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	prev := dao.RedisClient
+	dao.RedisClient = client
+	t.Cleanup(func() {
+		dao.RedisClient = prev
+		client.Close()
+	})
+	return mr
+}
+
+// loadTestConfig 切换到仓库根目录并加载 config/test 下的配置
+func loadTestConfig(t *testing.T) {
+	t.Helper()
+	t.Chdir("../..")
+	config.GetConf()
+}
+
+// setupTestDB 连接 TEST_DATABASE_DSN 指定的 PostgreSQL（需要安装 pgvector），未设置时跳过测试
+// 每个测试在单独的 schema 中执行 user.sql 建表，结束后删除
+func setupTestDB(t *testing.T) {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set")
+	}
+	loadTestConfig(t)
+	schemaSQL, err := os.ReadFile("user.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gormConf := &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)}
+	admin, err := gorm.Open(postgres.Open(dsn), gormConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatal(err)
+	}
+	// 连接池中的每个连接都要使用测试 schema，因此写在 DSN 中
+	searchPath := "search_path=" + schema + ",public"
+	if strings.Contains(dsn, "://") {
+		if strings.Contains(dsn, "?") {
+			dsn += "&" + searchPath
+		} else {
+			dsn += "?" + searchPath
+		}
+	} else {
+		dsn += " " + searchPath
+	}
+	db, err := gorm.Open(postgres.Open(dsn), gormConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec(string(schemaSQL)).Error; err != nil {
+		t.Fatal(err)
+	}
+	prev := dao.DB
+	dao.DB = db
+	t.Cleanup(func() {
+		dao.DB = prev
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+}
+
+// setupDryRunDB 使用不连接数据库的 DryRun 模式替换全局数据库连接，只生成 SQL 不执行
+// 用于审计日志等写入失败不影响流程的测试，不需要 PostgreSQL
+func setupDryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+		Logger:                 logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := dao.DB
+	dao.DB = db
+	t.Cleanup(func() { dao.DB = prev })
+	return db
+}
+
+// setupTestNotifier 使用内存通知替换全局通知发送方式，测试中可以读取重置凭证
+func setupTestNotifier(t *testing.T) *notify.MemoryNotifier {
+	t.Helper()
+	notifier := notify.NewMemoryNotifier()
+	prev := notify.Default
+	notify.Default = notifier
+	t.Cleanup(func() { notify.Default = prev })
+	return notifier
+}
@@ -0,0 +1,491 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/HCH1212/taxin/config"
+	"github.com/HCH1212/taxin/internal/dao"
+	"github.com/HCH1212/taxin/internal/model"
+	"github.com/HCH1212/taxin/internal/utils"
+	"github.com/go-redis/redis/v8"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/sync/errgroup"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// 批量导入导出的默认参数
+const (
+	defaultImportBatchSize = 500 // 每批导入的行数，同一批的喜好一起生成向量、一起写入数据库
+	exportPageSize         = 500 // 导出时每次查询的用户数
+)
+
+// 每行的导入结果
+const (
+	ImportStatusCreated = "created" // 新建用户
+	ImportStatusExists  = "exists"  // 用户名已注册且密码和喜好一致，与 Register 一样返回已有的 user_id
+	ImportStatusFailed  = "failed"  // 校验或写入失败
+)
+
+// ImportRow 批量导入的一行，password 和 password_hash 二选一
+type ImportRow struct {
+	Username     string   `json:"username"`
+	Password     string   `json:"password,omitempty"`      // 明文密码
+	PasswordHash string   `json:"password_hash,omitempty"` // 从旧系统迁移的 bcrypt 哈希
+	Like         []string `json:"like"`
+}
+
+// ImportResult 一行的导入结果，Row 从 1 开始，与加入导入器的顺序一致
+type ImportResult struct {
+	Row             int    `json:"row"`
+	Username        string `json:"username"`
+	UserID          string `json:"user_id,omitempty"`
+	Status          string `json:"status"`
+	EmbeddingStatus string `json:"embedding_status,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+// ExportedUser ExportUsers 导出的一行，可以直接作为 ImportRow 再次导入
+type ExportedUser struct {
+	UserID          string     `json:"user_id"`
+	Username        string     `json:"username"`
+	PasswordHash    string     `json:"password_hash,omitempty"`
+	Like            []string   `json:"like"`
+	Role            string     `json:"role"`
+	DisabledAt      *time.Time `json:"disabled_at,omitempty"`
+	EmbeddingStatus string     `json:"embedding_status"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// UserImporter 按批导入用户，去重规则与 Register 一致：用户名已注册且密码和喜好一致时返回已有的 user_id，
+// 不一致时该行失败；同一次导入中重复的用户名只创建一次
+type UserImporter struct {
+	batchSize int
+	pending   []importItem
+	rows      int
+	seen      map[string]importSeen // 本次导入中已创建或已存在的用户名
+}
+
+// importSeen 本次导入中已处理过的用户名，重复出现时与第一次出现的行比较请求指纹
+type importSeen struct {
+	userID string
+	row    ImportRow
+}
+
+// importItem 待导入的一行及其结果，导入过程中直接填写结果
+type importItem struct {
+	row    ImportRow
+	result *ImportResult
+}
+
+// NewUserImporter 创建导入器，batchSize <= 0 时使用默认值
+func NewUserImporter(batchSize int) *UserImporter {
+	if batchSize <= 0 {
+		batchSize = defaultImportBatchSize
+	}
+	return &UserImporter{
+		batchSize: batchSize,
+		seen:      make(map[string]importSeen),
+	}
+}
+
+// Add 加入一行，攒满一批时执行导入并返回这一批的结果，否则返回 nil
+func (im *UserImporter) Add(ctx context.Context, row ImportRow) []ImportResult {
+	im.rows++
+	im.pending = append(im.pending, importItem{
+		row:    row,
+		result: &ImportResult{Row: im.rows, Username: row.Username},
+	})
+	if len(im.pending) < im.batchSize {
+		return nil
+	}
+	return im.Flush(ctx)
+}
+
+// Flush 导入剩余的行并返回结果
+func (im *UserImporter) Flush(ctx context.Context) []ImportResult {
+	if len(im.pending) == 0 {
+		return nil
+	}
+	items := im.pending
+	im.pending = nil
+	im.importBatch(ctx, items)
+	results := make([]ImportResult, 0, len(items))
+	for _, item := range items {
+		results = append(results, *item.result)
+	}
+	return results
+}
+
+// importBatch 校验、去重、加密密码、批量生成向量并写入一批用户
+func (im *UserImporter) importBatch(ctx context.Context, items []importItem) {
+	// 校验，并找出本批中需要查询是否已注册的用户名
+	var valid []importItem
+	first := make(map[string]importItem)
+	var duplicates []importItem
+	for _, item := range items {
+		if err := validateImportRow(item.row); err != nil {
+			failImport(item.result, err)
+			continue
+		}
+		if seen, ok := im.seen[item.row.Username]; ok {
+			if sameImportFingerprint(seen.row, item.row) {
+				item.result.UserID = seen.userID
+				item.result.Status = ImportStatusExists
+			} else {
+				failImport(item.result, errUsernameTaken)
+			}
+			continue
+		}
+		if _, ok := first[item.row.Username]; ok {
+			duplicates = append(duplicates, item)
+			continue
+		}
+		first[item.row.Username] = item
+		valid = append(valid, item)
+	}
+	// 同一批中重复的用户名以第一行的结果为准，密码或喜好不一致时视为用户名已被占用
+	defer func() {
+		for _, item := range duplicates {
+			f := first[item.row.Username]
+			switch {
+			case !sameImportFingerprint(f.row, item.row):
+				failImport(item.result, errUsernameTaken)
+			case f.result.Status == ImportStatusFailed:
+				item.result.Status = ImportStatusFailed
+				item.result.Error = f.result.Error
+			default:
+				item.result.UserID = f.result.UserID
+				item.result.Status = ImportStatusExists
+			}
+		}
+	}()
+	if len(valid) == 0 {
+		return
+	}
+
+	toCreate, err := im.checkRepeats(ctx, valid)
+	if err != nil {
+		for _, item := range valid {
+			failImport(item.result, err)
+		}
+		return
+	}
+	if len(toCreate) == 0 {
+		return
+	}
+
+	users := buildImportUsers(ctx, toCreate)
+	var userLikes []model.UserLike
+	var jobUserIDs []string
+	if embedImportUsers(ctx, toCreate, users) {
+		for i := range users {
+			if users[i] != nil {
+				userLikes = append(userLikes, users[i].likes...)
+			}
+		}
+	} else {
+		for _, u := range users {
+			if u != nil {
+				jobUserIDs = append(jobUserIDs, u.user.UserID)
+			}
+		}
+	}
+
+	// 整批写入，失败时逐个写入以确定是哪一行出错
+	err = dao.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		batch := make([]model.User, 0, len(users))
+		for _, u := range users {
+			if u != nil {
+				batch = append(batch, u.user)
+			}
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := tx.CreateInBatches(&batch, 100).Error; err != nil {
+			return err
+		}
+		if err := model.CreateEmbeddingJobs(tx, jobUserIDs); err != nil {
+			return err
+		}
+		if len(userLikes) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(&userLikes, 100).Error
+	})
+	if err != nil {
+		for i, u := range users {
+			if u == nil {
+				continue
+			}
+			err := createImportUser(ctx, u)
+			if err == nil {
+				continue
+			}
+			users[i] = nil
+			// 与 Register 一样，用户名被并发注册时按请求指纹判断是否为重复请求
+			if model.IsUniqueViolation(err) {
+				item := toCreate[i]
+				var userID string
+				userID, err = checkRegisterRepeat(ctx, item.row.Username, item.row.Password, item.row.PasswordHash, item.row.Like)
+				if err == nil && userID == "" {
+					err = errUsernameTaken
+				}
+				if err == nil {
+					item.result.UserID = userID
+					item.result.Status = ImportStatusExists
+					im.seen[item.row.Username] = importSeen{userID: userID, row: item.row}
+					continue
+				}
+			}
+			failImport(toCreate[i].result, err)
+		}
+	}
+
+	// 写入注册幂等键，失败时不影响结果，Register 会回退到数据库查询
+	_, err = dao.RedisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, u := range users {
+			if u != nil {
+				pipe.Set(ctx, registerRedisKey(u.user.Username), u.user.UserID, registerRedisTTL)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("import users: store register info: %v", err)
+	}
+	for i, u := range users {
+		if u == nil {
+			continue
+		}
+		r := toCreate[i].result
+		r.UserID = u.user.UserID
+		r.Status = ImportStatusCreated
+		r.EmbeddingStatus = u.user.EmbeddingStatus
+		im.seen[u.user.Username] = importSeen{userID: u.user.UserID, row: toCreate[i].row}
+	}
+}
+
+// checkRepeats 按与 Register 相同的规则检查已注册的用户名，返回需要新建的行
+// 已注册且请求指纹一致的行标记为已存在，不一致的标记为失败
+func (im *UserImporter) checkRepeats(ctx context.Context, items []importItem) ([]importItem, error) {
+	usernames := make([]string, 0, len(items))
+	for _, item := range items {
+		usernames = append(usernames, item.row.Username)
+	}
+	registered, err := registeredUserIDs(ctx, usernames)
+	if err != nil {
+		return nil, err
+	}
+	var toCreate, repeats []importItem
+	for _, item := range items {
+		if _, ok := registered[item.row.Username]; ok {
+			repeats = append(repeats, item)
+		} else {
+			toCreate = append(toCreate, item)
+		}
+	}
+	// 比较明文密码需要计算 bcrypt，并发执行
+	userIDs := make([]string, len(repeats))
+	errs := make([]error, len(repeats))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(runtime.NumCPU())
+	for i, item := range repeats {
+		g.Go(func() error {
+			userIDs[i], errs[i] = checkRegisterRepeat(gctx, item.row.Username, item.row.Password, item.row.PasswordHash, item.row.Like)
+			return nil
+		})
+	}
+	_ = g.Wait()
+	for i, item := range repeats {
+		switch {
+		case errs[i] != nil:
+			failImport(item.result, errs[i])
+		case userIDs[i] == "":
+			// 幂等键对应的用户已不存在
+			toCreate = append(toCreate, item)
+		default:
+			item.result.UserID = userIDs[i]
+			item.result.Status = ImportStatusExists
+			im.seen[item.row.Username] = importSeen{userID: userIDs[i], row: item.row}
+		}
+	}
+	return toCreate, nil
+}
+
+// sameImportFingerprint 同一次导入中重复的用户名，密码和喜好都一致时才视为重复的行
+func sameImportFingerprint(a, b ImportRow) bool {
+	return a.Password == b.Password && a.PasswordHash == b.PasswordHash && slices.Equal(a.Like, b.Like)
+}
+
+// importUser 待写入的用户及其喜好向量
+type importUser struct {
+	user  model.User
+	likes []model.UserLike
+}
+
+// buildImportUsers 并发加密密码并组装用户，失败的行对应位置为 nil
+func buildImportUsers(ctx context.Context, items []importItem) []*importUser {
+	users := make([]*importUser, len(items))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(runtime.NumCPU())
+	for i, item := range items {
+		g.Go(func() error {
+			if gctx.Err() != nil {
+				failImport(item.result, gctx.Err())
+				return nil
+			}
+			passwordHash := item.row.PasswordHash
+			if passwordHash == "" {
+				hash, err := utils.HashPassword(item.row.Password)
+				if err != nil {
+					failImport(item.result, err)
+					return nil
+				}
+				passwordHash = hash
+			}
+			likeJSON, err := json.Marshal(item.row.Like)
+			if err != nil {
+				failImport(item.result, err)
+				return nil
+			}
+			users[i] = &importUser{user: model.User{
+				Username:        item.row.Username,
+				UserID:          utils.GenerateUUID(),
+				Password:        passwordHash,
+				Like:            datatypes.JSON(likeJSON),
+				EmbeddingStatus: model.EmbeddingStatusPending,
+			}}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return users
+}
+
+// embedImportUsers 把一批用户的喜好合并后批量生成向量
+// 异步模式或向量服务不可用时返回 false，由后台任务补全
+func embedImportUsers(ctx context.Context, items []importItem, users []*importUser) bool {
+	if config.GetConf().Embedding.Async {
+		return false
+	}
+	var likes []string
+	for i, u := range users {
+		if u != nil {
+			likes = append(likes, items[i].row.Like...)
+		}
+	}
+	if len(likes) == 0 {
+		return false
+	}
+	likeEmbeddings, err := utils.GenerateLikeEmbeddings(ctx, likes)
+	if err != nil {
+		log.Printf("import users: generate embeddings, falling back to embedding jobs: %v", err)
+		return false
+	}
+	embeddingModel := utils.EmbeddingModel()
+	offset := 0
+	for i, u := range users {
+		if u == nil {
+			continue
+		}
+		userLikes := items[i].row.Like
+		embeddings := likeEmbeddings[offset : offset+len(userLikes)]
+		offset += len(userLikes)
+		embedding, err := utils.ProfileEmbedding(embeddings)
+		if err != nil {
+			failImport(items[i].result, err)
+			users[i] = nil
+			continue
+		}
+		u.user.LikeEmbedding = &embedding
+		u.user.EmbeddingStatus = model.EmbeddingStatusReady
+		u.user.EmbeddingModel = embeddingModel
+		u.user.EmbeddingDim = len(embedding.Slice())
+		u.likes = model.NewUserLikes(u.user.UserID, userLikes, embeddings, embeddingModel)
+	}
+	return true
+}
+
+// createImportUser 单独写入一个用户，与 Register 一致
+func createImportUser(ctx context.Context, u *importUser) error {
+	return dao.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := model.CreateUser(tx, &u.user); err != nil {
+			return err
+		}
+		if u.user.EmbeddingStatus == model.EmbeddingStatusPending {
+			return model.CreateEmbeddingJob(tx, u.user.UserID)
+		}
+		return model.CreateUserLikes(tx, u.likes)
+	})
+}
+
+// validateImportRow 校验必填字段，password_hash 必须是 bcrypt 哈希
+func validateImportRow(row ImportRow) error {
+	if row.Username == "" || len(row.Like) == 0 {
+		return errors.New("username and like are required")
+	}
+	if (row.Password == "") == (row.PasswordHash == "") {
+		return errors.New("exactly one of password and password_hash is required")
+	}
+	if row.PasswordHash != "" {
+		if _, err := bcrypt.Cost([]byte(row.PasswordHash)); err != nil {
+			return errors.New("password_hash is not a bcrypt hash")
+		}
+	}
+	for _, like := range row.Like {
+		if like == "" {
+			return errors.New("like must not be empty")
+		}
+	}
+	return nil
+}
+
+func failImport(result *ImportResult, err error) {
+	result.Status = ImportStatusFailed
+	result.Error = err.Error()
+}
+
+// WriteUsersJSONL 将符合条件的用户按主键顺序逐行写入 w，返回写入的用户数
+// includePasswordHash 为 true 时导出密码哈希，用于迁移到其他环境
+func WriteUsersJSONL(ctx context.Context, w io.Writer, filter model.UserFilter, includePasswordHash bool) (int, error) {
+	enc := json.NewEncoder(w)
+	var afterID uint
+	count := 0
+	for {
+		users, err := model.ListUsers(dao.DB.WithContext(ctx), filter, afterID, exportPageSize)
+		if err != nil {
+			return count, err
+		}
+		for _, user := range users {
+			row := ExportedUser{
+				UserID:          user.UserID,
+				Username:        user.Username,
+				Like:            user.GetLikeList(),
+				Role:            user.Role,
+				DisabledAt:      user.DisabledAt,
+				EmbeddingStatus: user.EmbeddingStatus,
+				CreatedAt:       user.CreatedAt,
+			}
+			if includePasswordHash {
+				row.PasswordHash = user.Password
+			}
+			if err := enc.Encode(row); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if len(users) < exportPageSize {
+			return count, nil
+		}
+		afterID = users[len(users)-1].ID
+	}
+}
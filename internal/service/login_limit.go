@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/HCH1212/taxin/config"
+	"github.com/HCH1212/taxin/internal/dao"
+	"github.com/HCH1212/taxin/internal/model"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// 未配置时登录防暴力破解的默认参数
+const (
+	defaultLoginWindow           = 15 * time.Minute
+	defaultLoginMaxPerIP         = 50
+	defaultLoginDelayAfter       = 3
+	defaultLoginDelayBase        = time.Second
+	defaultLoginDelayMax         = 30 * time.Second
+	defaultLoginLockoutThreshold = 10
+	defaultLoginLockoutDuration  = 15 * time.Minute
+)
+
+// errAccountDisabled 账号已被管理员禁用
+var errAccountDisabled = status.Error(grpccodes.PermissionDenied, "account disabled")
+
+// loginAccountKey 登录限流使用的账号标识，账号不存在时使用请求中的标识，保证与存在的账号表现一致
+func loginAccountKey(user *model.User, userID, username string) string {
+	switch {
+	case user != nil:
+		return user.UserID
+	case username != "":
+		return "name:" + username
+	default:
+		return "id:" + userID
+	}
+}
+
+// checkLoginAllowed 校验密码前检查账号是否被锁定、IP 是否被限流以及是否还需要等待
+func checkLoginAllowed(ctx context.Context, account, ip string) error {
+	conf := loginLimitConf()
+	until, err := dao.GetAccountLock(ctx, account)
+	if err != nil {
+		return err
+	}
+	if !until.IsZero() {
+		return retryError(grpccodes.PermissionDenied,
+			fmt.Sprintf("account temporarily locked until %s", until.Format(time.RFC3339)), time.Until(until))
+	}
+	if ip != "" {
+		count, retryAfter, err := dao.CountLoginFailures(ctx, "ip", ip, conf.Window)
+		if err != nil {
+			return err
+		}
+		if count >= int64(conf.MaxPerIP) {
+			return retryError(grpccodes.ResourceExhausted, "too many failed login attempts", retryAfter)
+		}
+	}
+	delay, err := dao.GetLoginDelay(ctx, account)
+	if err != nil {
+		return err
+	}
+	if delay > 0 {
+		return retryError(grpccodes.ResourceExhausted, "too many failed login attempts", delay)
+	}
+	return nil
+}
+
+// recordLoginFailure 记录一次密码错误，达到阈值时锁定账号并写入审计日志，锁定时返回锁定错误
+func recordLoginFailure(ctx context.Context, span trace.Span, account, userID, ip string) error {
+	conf := loginLimitConf()
+	if ip != "" {
+		count, err := dao.RecordLoginFailure(ctx, "ip", ip, conf.Window)
+		if err != nil {
+			return err
+		}
+		// 只在刚达到阈值时记录一次
+		if count == int64(conf.MaxPerIP) {
+			span.AddEvent("login rate limited", trace.WithAttributes(attribute.String("ip", ip)))
+			recordAuditEvent(ctx, userID, model.AuditEventLoginRateLimited, ip, map[string]interface{}{
+				"failures": count,
+				"window":   conf.Window.String(),
+			})
+		}
+	}
+	failures, err := dao.RecordLoginFailure(ctx, "user", account, conf.Window)
+	if err != nil {
+		return err
+	}
+	if failures >= int64(conf.LockoutThreshold) {
+		until, err := dao.LockAccount(ctx, account, conf.LockoutDuration)
+		if err != nil {
+			return err
+		}
+		if err := dao.ClearLoginFailures(ctx, account); err != nil {
+			return err
+		}
+		span.AddEvent("account locked", trace.WithAttributes(
+			attribute.Int64("failures", failures),
+			attribute.String("locked_until", until.Format(time.RFC3339)),
+		))
+		recordAuditEvent(ctx, userID, model.AuditEventLoginLocked, ip, map[string]interface{}{
+			"failures":     failures,
+			"locked_until": until.Format(time.RFC3339),
+		})
+		return retryError(grpccodes.PermissionDenied,
+			fmt.Sprintf("account temporarily locked until %s", until.Format(time.RFC3339)), conf.LockoutDuration)
+	}
+	// 失败次数越多，下次允许尝试前需要等待的时间越长
+	if failures >= int64(conf.DelayAfter) {
+		delay := conf.DelayBase << (failures - int64(conf.DelayAfter))
+		if delay <= 0 || delay > conf.DelayMax {
+			delay = conf.DelayMax
+		}
+		if err := dao.SetLoginDelay(ctx, account, delay); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retryError 返回带有重试时间的 gRPC 错误
+func retryError(code grpccodes.Code, msg string, retryAfter time.Duration) error {
+	if retryAfter < time.Second {
+		retryAfter = time.Second
+	}
+	st, err := status.New(code, msg).WithDetails(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(retryAfter.Round(time.Second)),
+	})
+	if err != nil {
+		return status.Error(code, msg)
+	}
+	return st.Err()
+}
+
+// recordAuditEvent 写入审计日志，失败只记录日志，不影响请求
+func recordAuditEvent(ctx context.Context, userID, event, ip string, detail map[string]interface{}) {
+	data, err := json.Marshal(detail)
+	if err != nil {
+		log.Printf("audit: marshal %s detail: %v", event, err)
+		return
+	}
+	err = model.CreateAuditEvent(dao.DB.WithContext(ctx), &model.AuditEvent{
+		UserID: userID,
+		Event:  event,
+		IP:     ip,
+		Detail: data,
+	})
+	if err != nil {
+		log.Printf("audit: record %s for user %s: %v", event, userID, err)
+	}
+}
+
+// loginLimitConf 读取登录防暴力破解配置，未配置的项使用默认值
+func loginLimitConf() config.LoginLimit {
+	conf := config.GetConf().LoginLimit
+	if conf.Window <= 0 {
+		conf.Window = defaultLoginWindow
+	}
+	if conf.MaxPerIP <= 0 {
+		conf.MaxPerIP = defaultLoginMaxPerIP
+	}
+	if conf.DelayAfter <= 0 {
+		conf.DelayAfter = defaultLoginDelayAfter
+	}
+	if conf.DelayBase <= 0 {
+		conf.DelayBase = defaultLoginDelayBase
+	}
+	if conf.DelayMax <= 0 {
+		conf.DelayMax = defaultLoginDelayMax
+	}
+	if conf.LockoutThreshold <= 0 {
+		conf.LockoutThreshold = defaultLoginLockoutThreshold
+	}
+	if conf.LockoutDuration <= 0 {
+		conf.LockoutDuration = defaultLoginLockoutDuration
+	}
+	return conf
+}
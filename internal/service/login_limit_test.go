@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	pb "github.com/HCH1212/taxin/api/pb/user"
+	"github.com/HCH1212/taxin/internal/dao"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// retryAfter 读取错误详情中的重试时间
+func retryAfter(t *testing.T, err error) time.Duration {
+	t.Helper()
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			return info.RetryDelay.AsDuration()
+		}
+	}
+	t.Fatalf("no RetryInfo in %v", err)
+	return 0
+}
+
+func TestLoginLockout(t *testing.T) {
+	loadTestConfig(t)
+	setupTestRedis(t)
+	setupDryRunDB(t)
+	ctx := context.Background()
+	span := trace.SpanFromContext(ctx)
+	conf := loginLimitConf()
+
+	for i := 1; i < conf.LockoutThreshold; i++ {
+		require.NoError(t, recordLoginFailure(ctx, span, "user-lock", "user-lock", ""))
+	}
+	// 达到阈值的这次失败直接返回锁定错误
+	err := recordLoginFailure(ctx, span, "user-lock", "user-lock", "")
+	assert.Equal(t, grpccodes.PermissionDenied, status.Code(err))
+	assert.Equal(t, conf.LockoutDuration, retryAfter(t, err))
+
+	// 锁定期间拒绝登录，重试时间为剩余的锁定时间
+	err = checkLoginAllowed(ctx, "user-lock", "")
+	assert.Equal(t, grpccodes.PermissionDenied, status.Code(err))
+	assert.InDelta(t, conf.LockoutDuration.Seconds(), retryAfter(t, err).Seconds(), 1)
+}
+
+func TestLoginDelayRetryAfter(t *testing.T) {
+	loadTestConfig(t)
+	mr := setupTestRedis(t)
+	setupDryRunDB(t)
+	ctx := context.Background()
+	span := trace.SpanFromContext(ctx)
+	conf := loginLimitConf()
+
+	for i := 1; i < conf.DelayAfter; i++ {
+		require.NoError(t, recordLoginFailure(ctx, span, "user-delay", "user-delay", ""))
+		require.NoError(t, checkLoginAllowed(ctx, "user-delay", ""))
+	}
+	// 从 DelayAfter 次开始需要等待，每多失败一次等待时间翻倍
+	require.NoError(t, recordLoginFailure(ctx, span, "user-delay", "user-delay", ""))
+	err := checkLoginAllowed(ctx, "user-delay", "")
+	assert.Equal(t, grpccodes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, conf.DelayBase, retryAfter(t, err))
+
+	require.NoError(t, recordLoginFailure(ctx, span, "user-delay", "user-delay", ""))
+	err = checkLoginAllowed(ctx, "user-delay", "")
+	assert.Equal(t, 2*conf.DelayBase, retryAfter(t, err))
+
+	// 等待时间过后允许再次尝试
+	mr.FastForward(2 * conf.DelayBase)
+	assert.NoError(t, checkLoginAllowed(ctx, "user-delay", ""))
+}
+
+func TestLoginLimitScopes(t *testing.T) {
+	loadTestConfig(t)
+	setupTestRedis(t)
+	setupDryRunDB(t)
+	ctx := context.Background()
+	span := trace.SpanFromContext(ctx)
+	conf := loginLimitConf()
+
+	// 同一 IP 对不同账号的失败累计到 IP 上，每个账号的失败次数都低于等待阈值
+	for i := 0; i < conf.MaxPerIP; i++ {
+		require.NoError(t, recordLoginFailure(ctx, span, fmt.Sprintf("name:user%d", i), "", "10.0.0.1"))
+	}
+	err := checkLoginAllowed(ctx, "name:other", "10.0.0.1")
+	assert.Equal(t, grpccodes.ResourceExhausted, status.Code(err))
+	assert.Greater(t, retryAfter(t, err), time.Duration(0))
+
+	// 其他 IP 上的同一账号不受影响
+	assert.NoError(t, checkLoginAllowed(ctx, "name:user0", "10.0.0.2"))
+
+	// 同一账号在不同 IP 上的失败累计到账号上
+	for i := 0; i < conf.DelayAfter; i++ {
+		require.NoError(t, recordLoginFailure(ctx, span, "user-roaming", "user-roaming", fmt.Sprintf("10.0.1.%d", i)))
+	}
+	err = checkLoginAllowed(ctx, "user-roaming", "10.0.1.100")
+	assert.Equal(t, grpccodes.ResourceExhausted, status.Code(err))
+}
+
+// 登录成功时清除账号的失败次数和等待时间，IP 上的失败次数保留
+func TestClearLoginFailures(t *testing.T) {
+	loadTestConfig(t)
+	setupTestRedis(t)
+	setupDryRunDB(t)
+	ctx := context.Background()
+	span := trace.SpanFromContext(ctx)
+	conf := loginLimitConf()
+
+	for i := 0; i < conf.DelayAfter; i++ {
+		require.NoError(t, recordLoginFailure(ctx, span, "user-reset", "user-reset", "10.0.0.4"))
+	}
+	require.Error(t, checkLoginAllowed(ctx, "user-reset", "10.0.0.4"))
+	require.NoError(t, dao.ClearLoginFailures(ctx, "user-reset"))
+	assert.NoError(t, checkLoginAllowed(ctx, "user-reset", "10.0.0.4"))
+
+	for i := 1; i < conf.DelayAfter; i++ {
+		require.NoError(t, recordLoginFailure(ctx, span, "user-reset", "user-reset", "10.0.0.4"))
+	}
+	assert.NoError(t, checkLoginAllowed(ctx, "user-reset", "10.0.0.4"))
+	count, _, err := dao.CountLoginFailures(ctx, "ip", "10.0.0.4", conf.Window)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2*conf.DelayAfter-1), count)
+}
+
+func TestLoginSuccessResetsFailures(t *testing.T) {
+	setupTestRedis(t)
+	setupTestDB(t)
+	createTestUser(t, "user-login", "carol", "right-password")
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &fakeAddr{"10.0.0.3:5000"}})
+	u := &UserService{}
+	conf := loginLimitConf()
+
+	wrong := &pb.LoginReq{Username: "carol", Password: "wrong-password"}
+	right := &pb.LoginReq{Username: "carol", Password: "right-password"}
+	for i := 1; i < conf.DelayAfter; i++ {
+		_, err := u.Login(ctx, wrong)
+		assert.Equal(t, errInvalidCredentials, err)
+	}
+	_, err := u.Login(ctx, right)
+	require.NoError(t, err)
+
+	// 登录成功后账号的失败次数重新计算，再失败 DelayAfter-1 次也不需要等待
+	for i := 1; i < conf.DelayAfter; i++ {
+		_, err := u.Login(ctx, wrong)
+		assert.Equal(t, errInvalidCredentials, err)
+	}
+	_, err = u.Login(ctx, right)
+	assert.NoError(t, err)
+}
+
+// fakeAddr 测试中使用的客户端地址
+type fakeAddr struct {
+	addr string
+}
+
+func (a *fakeAddr) Network() string { return "tcp" }
+func (a *fakeAddr) String() string  { return a.addr }
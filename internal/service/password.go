@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	pb "github.com/HCH1212/taxin/api/pb/user"
+	"github.com/HCH1212/taxin/config"
+	"github.com/HCH1212/taxin/internal/dao"
+	"github.com/HCH1212/taxin/internal/model"
+	"github.com/HCH1212/taxin/internal/notify"
+	"github.com/HCH1212/taxin/internal/utils"
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	grpccodes "google.golang.org/grpc/codes"
+	"gorm.io/gorm"
+)
+
+// 未配置时重置密码的默认参数
+const (
+	defaultPasswordResetTTL        = 15 * time.Minute
+	defaultPasswordResetWindow     = time.Hour
+	defaultPasswordResetMaxPerUser = 3
+	defaultPasswordResetMaxPerIP   = 20
+)
+
+// ChangePassword 验证旧密码后修改密码，并使已签发的所有 token 失效，旧密码错误与登录失败一样计入限流
+func (u *UserService) ChangePassword(ctx context.Context, req *pb.ChangePasswordReq) (*pb.ChangePasswordResp, error) {
+	tr := otel.Tracer("user-service")
+	_, span := tr.Start(ctx, "ChangePassword")
+	defer span.End()
+	// 从上下文中获取用户 ID
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "missing user ID in context")
+		return nil, errors.New("missing user ID in context")
+	}
+	span.SetAttributes(attribute.String("user_id", userID))
+	// 参数校验
+	if req.OldPassword == "" || req.NewPassword == "" {
+		span.SetStatus(codes.Error, "invalid request")
+		return nil, errors.New("invalid request")
+	}
+	// 查询用户信息
+	user, err := model.GetUserByUserID(dao.DB, userID)
+	if err != nil {
+		span.SetStatus(codes.Error, "get user failed")
+		return nil, err
+	}
+	// 与登录共用失败次数和锁定，持有 access_token 也不能无限次尝试旧密码
+	account := loginAccountKey(user, userID, "")
+	ip := clientIP(ctx)
+	if err := checkLoginAllowed(ctx, account, ip); err != nil {
+		span.SetStatus(codes.Error, "change password rejected by rate limit")
+		return nil, err
+	}
+	// 验证旧密码
+	if !utils.VerifyPassword(user.Password, req.OldPassword) {
+		if err := recordLoginFailure(ctx, span, account, userID, ip); err != nil {
+			span.SetStatus(codes.Error, "record login failure failed")
+			return nil, err
+		}
+		span.SetStatus(codes.Error, "invalid password")
+		return nil, errors.New("invalid password")
+	}
+	if err := dao.ClearLoginFailures(ctx, account); err != nil {
+		span.SetStatus(codes.Error, "redis error")
+		return nil, err
+	}
+	if err := setPassword(ctx, userID, req.NewPassword); err != nil {
+		span.SetStatus(codes.Error, "set password failed")
+		return nil, err
+	}
+	// 为当前客户端签发新的 token
+	tokens, err := issueTokens(ctx, user)
+	if err != nil {
+		span.SetStatus(codes.Error, "generate access token failed")
+		return nil, err
+	}
+	span.AddEvent("change password success")
+	return &pb.ChangePasswordResp{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+	}, nil
+}
+
+// RequestPasswordReset 生成一次性重置凭证并通过通知发送给用户
+// 无论用户是否存在都返回成功，查询用户和发送通知在后台进行，响应耗时也不会暴露用户是否存在
+func (u *UserService) RequestPasswordReset(ctx context.Context, req *pb.RequestPasswordResetReq) (*pb.RequestPasswordResetResp, error) {
+	tr := otel.Tracer("user-service")
+	_, span := tr.Start(ctx, "RequestPasswordReset")
+	defer span.End()
+	// 参数校验
+	username := strings.TrimSpace(req.Username)
+	if username == "" {
+		span.SetStatus(codes.Error, "invalid request")
+		return nil, errors.New("invalid request")
+	}
+	conf := passwordResetConf()
+	// 按用户名和 IP 限制申请频率，在查询用户之前检查，用户不存在时表现一致
+	if err := checkPasswordResetAllowed(ctx, conf, username, clientIP(ctx)); err != nil {
+		span.SetStatus(codes.Error, "too many password reset requests")
+		return nil, err
+	}
+	// 请求结束后继续发送，不随请求取消
+	go sendPasswordReset(context.WithoutCancel(ctx), username, conf.TTL)
+	span.AddEvent("password reset requested")
+	return &pb.RequestPasswordResetResp{}, nil
+}
+
+// sendPasswordReset 查询用户，生成重置凭证并发送通知，用户不存在时什么也不做，失败只记录日志
+func sendPasswordReset(ctx context.Context, username string, ttl time.Duration) {
+	tr := otel.Tracer("user-service")
+	_, span := tr.Start(ctx, "SendPasswordReset")
+	defer span.End()
+	// 查询用户
+	userID, err := model.GetUserIDByUsername(dao.DB, username)
+	if err == gorm.ErrRecordNotFound {
+		span.AddEvent("user not found")
+		return
+	} else if err != nil {
+		span.SetStatus(codes.Error, "database error")
+		log.Printf("password reset: get user %s: %v", username, err)
+		return
+	}
+	span.SetAttributes(attribute.String("user_id", userID))
+	// 生成重置凭证，服务端只保存摘要
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		span.SetStatus(codes.Error, "generate reset token failed")
+		log.Printf("password reset: generate token for user %s: %v", userID, err)
+		return
+	}
+	if err := dao.SavePasswordResetToken(ctx, utils.HashToken(token), userID, ttl); err != nil {
+		span.SetStatus(codes.Error, "store reset token failed")
+		log.Printf("password reset: store token for user %s: %v", userID, err)
+		return
+	}
+	// 发送通知
+	err = notify.Default.SendPasswordReset(ctx, notify.PasswordReset{
+		UserID:    userID,
+		Username:  username,
+		Token:     token,
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, "send reset notification failed")
+		log.Printf("password reset: notify user %s: %v", userID, err)
+		return
+	}
+	span.AddEvent("password reset notification sent")
+}
+
+// ConfirmPasswordReset 使用重置凭证设置新密码，凭证使用后立即失效，并使已签发的所有 token 失效
+func (u *UserService) ConfirmPasswordReset(ctx context.Context, req *pb.ConfirmPasswordResetReq) (*pb.ConfirmPasswordResetResp, error) {
+	tr := otel.Tracer("user-service")
+	_, span := tr.Start(ctx, "ConfirmPasswordReset")
+	defer span.End()
+	// 参数校验
+	if req.Token == "" || req.NewPassword == "" {
+		span.SetStatus(codes.Error, "invalid request")
+		return nil, errors.New("invalid request")
+	}
+	// 取出并作废重置凭证
+	userID, err := dao.ConsumePasswordResetToken(ctx, utils.HashToken(req.Token))
+	if err == redis.Nil {
+		span.SetStatus(codes.Error, "invalid reset token")
+		return nil, errors.New("invalid or expired reset token")
+	} else if err != nil {
+		span.SetStatus(codes.Error, "redis error")
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("user_id", userID))
+	if err := setPassword(ctx, userID, req.NewPassword); err != nil {
+		span.SetStatus(codes.Error, "set password failed")
+		return nil, err
+	}
+	span.AddEvent("password reset success")
+	return &pb.ConfirmPasswordResetResp{}, nil
+}
+
+// setPassword 保存新密码，作废未使用的重置凭证，并使已签发的所有 token 失效
+func setPassword(ctx context.Context, userID, password string) error {
+	hashPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	if err := model.UpdateUser(dao.DB, userID, map[string]interface{}{"password": hashPassword}); err != nil {
+		return err
+	}
+	if err := dao.DeletePasswordResetToken(ctx, userID); err != nil {
+		return err
+	}
+	return revokeUserTokens(ctx, userID)
+}
+
+// checkPasswordResetAllowed 检查滑动窗口内的申请次数，未超过限制时记录本次申请
+func checkPasswordResetAllowed(ctx context.Context, conf config.PasswordReset, username, ip string) error {
+	limits := []struct {
+		scope string
+		id    string
+		max   int
+	}{
+		{"user", username, conf.MaxPerUser},
+		{"ip", ip, conf.MaxPerIP},
+	}
+	for _, l := range limits {
+		if l.id == "" {
+			continue
+		}
+		count, retryAfter, err := dao.CountPasswordResetRequests(ctx, l.scope, l.id, conf.Window)
+		if err != nil {
+			return err
+		}
+		if count >= int64(l.max) {
+			return retryError(grpccodes.ResourceExhausted, "too many password reset requests", retryAfter)
+		}
+	}
+	for _, l := range limits {
+		if l.id == "" {
+			continue
+		}
+		if _, err := dao.RecordPasswordResetRequest(ctx, l.scope, l.id, conf.Window); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func passwordResetConf() config.PasswordReset {
+	conf := config.GetConf().PasswordReset
+	if conf.TTL <= 0 {
+		conf.TTL = defaultPasswordResetTTL
+	}
+	if conf.Window <= 0 {
+		conf.Window = defaultPasswordResetWindow
+	}
+	if conf.MaxPerUser <= 0 {
+		conf.MaxPerUser = defaultPasswordResetMaxPerUser
+	}
+	if conf.MaxPerIP <= 0 {
+		conf.MaxPerIP = defaultPasswordResetMaxPerIP
+	}
+	return conf
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/HCH1212/taxin/api/pb/user"
+	"github.com/HCH1212/taxin/config"
+	"github.com/HCH1212/taxin/internal/dao"
+	"github.com/HCH1212/taxin/internal/model"
+	"github.com/HCH1212/taxin/internal/notify"
+	"github.com/HCH1212/taxin/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/datatypes"
+)
+
+// createTestUser 直接写入一个用户，跳过注册流程中的向量生成
+func createTestUser(t *testing.T, userID, username, password string) *model.User {
+	t.Helper()
+	hash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+	user := &model.User{
+		UserID:          userID,
+		Username:        username,
+		Password:        hash,
+		Like:            datatypes.JSON(`["reading"]`),
+		EmbeddingStatus: model.EmbeddingStatusPending,
+		Role:            model.RoleUser,
+	}
+	require.NoError(t, model.CreateUser(dao.DB, user))
+	return user
+}
+
+func TestPasswordResetFlow(t *testing.T) {
+	setupTestRedis(t)
+	setupTestDB(t)
+	notifier := setupTestNotifier(t)
+	ctx := context.Background()
+	createTestUser(t, "user-reset", "alice", "old-password")
+	u := &UserService{}
+
+	_, err := u.RequestPasswordReset(ctx, &pb.RequestPasswordResetReq{Username: "alice"})
+	require.NoError(t, err)
+	// 通知在后台发送
+	var msg notify.PasswordReset
+	require.Eventually(t, func() bool {
+		var ok bool
+		msg, ok = notifier.LastPasswordReset("alice")
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "user-reset", msg.UserID)
+
+	_, err = u.ConfirmPasswordReset(ctx, &pb.ConfirmPasswordResetReq{Token: msg.Token, NewPassword: "new-password"})
+	require.NoError(t, err)
+	user, err := model.GetUserByUserID(dao.DB, "user-reset")
+	require.NoError(t, err)
+	assert.True(t, utils.VerifyPassword(user.Password, "new-password"))
+
+	// 重置凭证只能使用一次
+	_, err = u.ConfirmPasswordReset(ctx, &pb.ConfirmPasswordResetReq{Token: msg.Token, NewPassword: "other-password"})
+	assert.Error(t, err)
+}
+
+func TestChangePasswordRateLimited(t *testing.T) {
+	setupTestRedis(t)
+	setupTestDB(t)
+	createTestUser(t, "user-change", "bob", "old-password")
+	ctx := context.WithValue(context.Background(), "user_id", "user-change")
+	u := &UserService{}
+	conf := loginLimitConf()
+
+	for i := 0; i < conf.DelayAfter; i++ {
+		_, err := u.ChangePassword(ctx, &pb.ChangePasswordReq{OldPassword: "wrong-password", NewPassword: "new-password"})
+		require.Error(t, err)
+		assert.NotEqual(t, grpccodes.ResourceExhausted, status.Code(err))
+	}
+	// 达到阈值后即使旧密码正确也要先等待
+	_, err := u.ChangePassword(ctx, &pb.ChangePasswordReq{OldPassword: "old-password", NewPassword: "new-password"})
+	assert.Equal(t, grpccodes.ResourceExhausted, status.Code(err))
+}
+
+func TestCheckPasswordResetAllowed(t *testing.T) {
+	mr := setupTestRedis(t)
+	ctx := context.Background()
+	conf := config.PasswordReset{Window: time.Hour, MaxPerUser: 2, MaxPerIP: 3}
+
+	require.NoError(t, checkPasswordResetAllowed(ctx, conf, "alice", "10.0.0.1"))
+	require.NoError(t, checkPasswordResetAllowed(ctx, conf, "alice", "10.0.0.1"))
+	err := checkPasswordResetAllowed(ctx, conf, "alice", "10.0.0.1")
+	assert.Equal(t, grpccodes.ResourceExhausted, status.Code(err))
+
+	// 同一 IP 申请其他用户名，计入 IP 的次数
+	require.NoError(t, checkPasswordResetAllowed(ctx, conf, "bob", "10.0.0.1"))
+	err = checkPasswordResetAllowed(ctx, conf, "carol", "10.0.0.1")
+	assert.Equal(t, grpccodes.ResourceExhausted, status.Code(err))
+
+	// 不与登录失败共用计数
+	for _, key := range mr.Keys() {
+		assert.NotContains(t, key, "login:")
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/HCH1212/taxin/api/pb/user"
+	"github.com/HCH1212/taxin/internal/dao"
+	"github.com/HCH1212/taxin/internal/model"
+	"github.com/HCH1212/taxin/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/datatypes"
+)
+
+// setupTestEmbedder 使用本地哈希向量生成器，同步注册时不依赖向量服务
+func setupTestEmbedder(t *testing.T) {
+	t.Helper()
+	prev := utils.DefaultEmbedder
+	utils.DefaultEmbedder = utils.NewHashEmbedder(utils.EmbeddingDimension)
+	t.Cleanup(func() { utils.DefaultEmbedder = prev })
+}
+
+func TestRegisterFingerprintMatches(t *testing.T) {
+	hash, err := utils.HashPassword("secret")
+	require.NoError(t, err)
+	user := &model.User{Password: hash, Like: datatypes.JSON(`["reading","hiking"]`)}
+
+	assert.True(t, registerFingerprintMatches(user, "secret", "", []string{"reading", "hiking"}))
+	assert.True(t, registerFingerprintMatches(user, "", hash, []string{"reading", "hiking"}))
+	assert.False(t, registerFingerprintMatches(user, "other", "", []string{"reading", "hiking"}))
+	// 喜好的顺序也属于指纹的一部分
+	assert.False(t, registerFingerprintMatches(user, "secret", "", []string{"hiking", "reading"}))
+	assert.False(t, registerFingerprintMatches(user, "secret", "", []string{"reading"}))
+}
+
+// 其他请求持有注册锁时直接返回 Aborted，且不能释放别人的锁
+func TestRegisterLockContention(t *testing.T) {
+	loadTestConfig(t)
+	mr := setupTestRedis(t)
+	setupDryRunDB(t)
+	ctx := context.Background()
+
+	locked, err := dao.AcquireRegisterLock(ctx, "dave", "other-request", registerLockTTL)
+	require.NoError(t, err)
+	require.True(t, locked)
+
+	u := &UserService{}
+	_, err = u.Register(ctx, &pb.RegisterReq{Username: "dave", Password: "secret", Like: []string{"reading"}})
+	assert.Equal(t, grpccodes.Aborted, status.Code(err))
+	assert.Equal(t, time.Second, retryAfter(t, err))
+
+	value, err := mr.Get("register:lock:dave")
+	require.NoError(t, err)
+	assert.Equal(t, "other-request", value)
+
+	// 锁释放后可以重新获取
+	require.NoError(t, dao.ReleaseRegisterLock(ctx, "dave", "other-request"))
+	locked, err = dao.AcquireRegisterLock(ctx, "dave", "next-request", registerLockTTL)
+	require.NoError(t, err)
+	assert.True(t, locked)
+}
+
+func TestRegisterFingerprintRejected(t *testing.T) {
+	setupTestRedis(t)
+	setupTestDB(t)
+	setupTestEmbedder(t)
+	ctx := context.Background()
+	u := &UserService{}
+
+	req := &pb.RegisterReq{Username: "erin", Password: "secret", Like: []string{"reading"}}
+	resp, err := u.Register(ctx, req)
+	require.NoError(t, err)
+
+	// 密码和喜好一致时视为重复请求，返回已有的 user_id
+	repeated, err := u.Register(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, resp.UserId, repeated.UserId)
+
+	_, err = u.Register(ctx, &pb.RegisterReq{Username: "erin", Password: "other", Like: []string{"reading"}})
+	assert.Equal(t, errUsernameTaken, err)
+	_, err = u.Register(ctx, &pb.RegisterReq{Username: "erin", Password: "secret", Like: []string{"hiking"}})
+	assert.Equal(t, errUsernameTaken, err)
+
+	// 幂等键过期后仍以数据库中的用户为准
+	require.NoError(t, dao.RedisClient.Del(ctx, registerRedisKey("erin")).Err())
+	_, err = u.Register(ctx, &pb.RegisterReq{Username: "erin", Password: "other", Like: []string{"reading"}})
+	assert.Equal(t, errUsernameTaken, err)
+}
+
+// 同一用户名的并发注册只创建一个用户，其余请求得到同一个 user_id 或需要重试
+func TestRegisterConcurrentDuplicate(t *testing.T) {
+	setupTestRedis(t)
+	setupTestDB(t)
+	setupTestEmbedder(t)
+	ctx := context.Background()
+	u := &UserService{}
+	req := &pb.RegisterReq{Username: "frank", Password: "secret", Like: []string{"reading"}}
+
+	const n = 8
+	var wg sync.WaitGroup
+	userIDs := make([]string, n)
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := u.Register(ctx, req)
+			if err == nil {
+				userIDs[i] = resp.UserId
+			}
+			errs[i] = err
+		}()
+	}
+	wg.Wait()
+
+	var created string
+	for i := 0; i < n; i++ {
+		if errs[i] != nil {
+			assert.Equal(t, grpccodes.Aborted, status.Code(errs[i]))
+			continue
+		}
+		if created == "" {
+			created = userIDs[i]
+		}
+		assert.Equal(t, created, userIDs[i])
+	}
+	require.NotEmpty(t, created)
+
+	// 被拒绝的请求重试后得到同一个 user_id
+	resp, err := u.Register(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, created, resp.UserId)
+	var count int64
+	require.NoError(t, dao.DB.Model(&model.User{}).Where("username = ?", "frank").Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"time"
+
+	pb "github.com/HCH1212/taxin/api/pb/user"
+	"github.com/HCH1212/taxin/internal/dao"
+	"github.com/HCH1212/taxin/internal/utils"
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// maxUserAgentLength user-agent 最大保存长度
+const maxUserAgentLength = 256
+
+// errSessionNotFound 会话不存在、已过期或不属于当前用户
+var errSessionNotFound = errors.New("session not found")
+
+// ListSessions 查看当前用户所有登录中的会话
+func (u *UserService) ListSessions(ctx context.Context, req *pb.ListSessionsReq) (*pb.ListSessionsResp, error) {
+	tr := otel.Tracer("user-service")
+	_, span := tr.Start(ctx, "ListSessions")
+	defer span.End()
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "missing user ID in context")
+		return nil, errors.New("missing user ID in context")
+	}
+	span.SetAttributes(attribute.String("user_id", userID))
+	sessions, err := dao.ListSessions(ctx, userID)
+	if err != nil {
+		span.SetStatus(codes.Error, "list sessions failed")
+		return nil, err
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+	var currentID string
+	if claims, ok := claimsFromContext(ctx); ok {
+		currentID = claims.SessionID
+	}
+	resp := &pb.ListSessionsResp{Sessions: make([]*pb.Session, 0, len(sessions))}
+	for _, s := range sessions {
+		resp.Sessions = append(resp.Sessions, &pb.Session{
+			SessionId:  s.ID,
+			UserAgent:  s.UserAgent,
+			Ip:         s.IP,
+			CreatedAt:  s.CreatedAt.Format("2006-01-02 15:04:05"),
+			LastSeenAt: s.LastSeenAt.Format("2006-01-02 15:04:05"),
+			Current:    s.ID == currentID,
+		})
+	}
+	span.SetAttributes(attribute.Int("session_count", len(resp.Sessions)))
+	return resp, nil
+}
+
+// RevokeSession 撤销当前用户的指定会话，该会话的 access_token 和 refresh_token 随之失效
+func (u *UserService) RevokeSession(ctx context.Context, req *pb.RevokeSessionReq) (*pb.RevokeSessionResp, error) {
+	tr := otel.Tracer("user-service")
+	_, span := tr.Start(ctx, "RevokeSession")
+	defer span.End()
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "missing user ID in context")
+		return nil, errors.New("missing user ID in context")
+	}
+	span.SetAttributes(attribute.String("user_id", userID))
+	// 参数校验
+	if req.SessionId == "" {
+		span.SetStatus(codes.Error, "invalid request")
+		return nil, errors.New("invalid request")
+	}
+	// 只能撤销自己的会话
+	session, err := dao.GetSession(ctx, req.SessionId)
+	if err == redis.Nil {
+		span.SetStatus(codes.Error, "session not found")
+		return nil, errSessionNotFound
+	} else if err != nil {
+		span.SetStatus(codes.Error, "redis error")
+		return nil, err
+	}
+	if session.UserID != userID {
+		span.SetStatus(codes.Error, "session not found")
+		return nil, errSessionNotFound
+	}
+	if err := revokeSession(ctx, userID, session.ID); err != nil {
+		span.SetStatus(codes.Error, "revoke session failed")
+		return nil, err
+	}
+	span.AddEvent("revoke session success")
+	return &pb.RevokeSessionResp{}, nil
+}
+
+// revokeSession 删除会话并作废对应的 refresh_token 家族
+func revokeSession(ctx context.Context, userID, sessionID string) error {
+	if err := dao.RevokeRefreshFamily(ctx, sessionID, utils.RefreshTokenTTL); err != nil {
+		return err
+	}
+	return dao.DeleteSession(ctx, userID, sessionID)
+}
+
+// newSession 根据请求的元数据和对端地址创建会话
+func newSession(ctx context.Context, userID string) dao.Session {
+	now := time.Now()
+	session := dao.Session{
+		ID:         utils.GenerateUUID(),
+		UserID:     userID,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ua := md.Get("user-agent"); len(ua) > 0 {
+			session.UserAgent = ua[0]
+			if len(session.UserAgent) > maxUserAgentLength {
+				session.UserAgent = session.UserAgent[:maxUserAgentLength]
+			}
+		}
+	}
+	session.IP = clientIP(ctx)
+	return session
+}
+
+// clientIP 获取请求的对端 IP
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
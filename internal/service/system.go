@@ -1,6 +1,7 @@
 package service
 
 import (
+	"bufio"
 	"io"
 	"os"
 
@@ -28,23 +29,50 @@ func (s *SystemService) SendFile(req *pb.SendFileReq, stream pb.SystemService_Se
 	defer file.Close()
 
 	// 读取文件内容并发送给客户端
-	buf := make([]byte, 1024*32) // 32KB分块
+	if err := streamChunks(file, func(chunk []byte) error {
+		return stream.Send(&pb.SendFileResp{Content: chunk})
+	}); err != nil {
+		span.SetStatus(codes.Error, "failed to send file")
+		return err
+	}
+	return nil
+}
+
+// streamChunkSize 流式返回时每个分块的大小
+const streamChunkSize = 1024 * 32 // 32KB分块
+
+// pipeChunks 在后台调用 write 生成内容，经过缓冲后按分块发送，写入方的小片段会合并成完整的分块
+// 发送失败时关闭管道，让写入方尽快退出
+func pipeChunks(write func(w io.Writer) error, send func(chunk []byte) error) error {
+	pr, pw := io.Pipe()
+	go func() {
+		bw := bufio.NewWriterSize(pw, streamChunkSize)
+		err := write(bw)
+		if err == nil {
+			err = bw.Flush()
+		}
+		pw.CloseWithError(err)
+	}()
+	err := streamChunks(pr, send)
+	pr.CloseWithError(err)
+	return err
+}
+
+// streamChunks 从 r 中按分块读取并依次调用 send 发送，不会把全部内容加载到内存
+func streamChunks(r io.Reader, send func(chunk []byte) error) error {
+	buf := make([]byte, streamChunkSize)
 	for {
-		n, err := file.Read(buf)
+		n, err := r.Read(buf)
+		if n > 0 {
+			if err := send(buf[:n]); err != nil {
+				return err
+			}
+		}
 		if err == io.EOF {
-			break
+			return nil
 		}
 		if err != nil {
-			span.SetStatus(codes.Error, "failed to read file")
-			return err
-		}
-
-		if err := stream.Send(&pb.SendFileResp{
-			Content: buf[:n],
-		}); err != nil {
-			span.SetStatus(codes.Error, "failed to send file")
 			return err
 		}
 	}
-	return nil
 }
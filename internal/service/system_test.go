@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/HCH1212/taxin/api/pb/system"
@@ -116,3 +117,50 @@ func TestSystemService_SendFile(t *testing.T) {
 		})
 	}
 }
+
+func TestStreamChunks(t *testing.T) {
+	content := strings.Repeat("x", streamChunkSize*2+10)
+	var chunks []int
+	var got strings.Builder
+	err := streamChunks(strings.NewReader(content), func(chunk []byte) error {
+		chunks = append(chunks, len(chunk))
+		got.Write(chunk)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{streamChunkSize, streamChunkSize, 10}, chunks)
+	assert.Equal(t, content, got.String())
+
+	// 发送失败时立即返回
+	err = streamChunks(strings.NewReader(content), func(chunk []byte) error {
+		return io.ErrClosedPipe
+	})
+	assert.ErrorIs(t, err, io.ErrClosedPipe)
+}
+
+func TestPipeChunks(t *testing.T) {
+	// 写入方每次只写一个字节，发送时仍按完整分块
+	total := streamChunkSize*2 + 10
+	var chunks []int
+	err := pipeChunks(func(w io.Writer) error {
+		for i := 0; i < total; i++ {
+			if _, err := w.Write([]byte{'x'}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, func(chunk []byte) error {
+		chunks = append(chunks, len(chunk))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{streamChunkSize, streamChunkSize, 10}, chunks)
+
+	// 写入失败时返回写入方的错误
+	err = pipeChunks(func(w io.Writer) error {
+		return io.ErrUnexpectedEOF
+	}, func(chunk []byte) error {
+		return nil
+	})
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
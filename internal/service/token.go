@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pb "github.com/HCH1212/taxin/api/pb/user"
+	"github.com/HCH1212/taxin/internal/dao"
+	"github.com/HCH1212/taxin/internal/model"
+	"github.com/HCH1212/taxin/internal/utils"
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// errInvalidRefreshToken refresh_token 不存在、已过期或已被作废
+var errInvalidRefreshToken = errors.New("invalid refresh token")
+
+// tokenPair 登录或刷新后返回给客户端的 token
+type tokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int64 // access_token 有效期，单位秒
+}
+
+// RefreshToken 使用 refresh_token 换取新的 access_token 和 refresh_token
+// 每个 refresh_token 只能使用一次，已使用的 refresh_token 再次出现说明可能被盗用，此时作废整个家族并撤销会话
+func (u *UserService) RefreshToken(ctx context.Context, req *pb.RefreshTokenReq) (*pb.RefreshTokenResp, error) {
+	tr := otel.Tracer("user-service")
+	_, span := tr.Start(ctx, "RefreshToken")
+	defer span.End()
+	// 参数校验
+	if req.RefreshToken == "" {
+		span.SetStatus(codes.Error, "invalid request")
+		return nil, errors.New("invalid request")
+	}
+	tokenHash := utils.HashToken(req.RefreshToken)
+	record, err := dao.GetRefreshToken(ctx, tokenHash)
+	if err == redis.Nil {
+		span.SetStatus(codes.Error, "refresh token not found")
+		return nil, errInvalidRefreshToken
+	} else if err != nil {
+		span.SetStatus(codes.Error, "redis error")
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("user_id", record.UserID))
+	// 家族已被作废
+	revoked, err := dao.IsRefreshFamilyRevoked(ctx, record.FamilyID)
+	if err != nil {
+		span.SetStatus(codes.Error, "redis error")
+		return nil, err
+	}
+	if revoked {
+		span.SetStatus(codes.Error, "refresh token family revoked")
+		return nil, errInvalidRefreshToken
+	}
+	// 标记为已使用，已使用过说明发生了重放，作废整个家族
+	first, err := dao.MarkRefreshTokenUsed(ctx, tokenHash, record.ExpiresAt)
+	if err != nil {
+		span.SetStatus(codes.Error, "redis error")
+		return nil, err
+	}
+	if !first {
+		span.AddEvent("refresh token reuse detected")
+		// 同时撤销会话，使该家族已签发的 access_token 立即失效
+		if err := revokeSession(ctx, record.UserID, record.FamilyID); err != nil {
+			span.SetStatus(codes.Error, "revoke refresh token family failed")
+			return nil, err
+		}
+		span.SetStatus(codes.Error, "refresh token reused")
+		return nil, errInvalidRefreshToken
+	}
+	// 修改密码等操作后，之前签发的 refresh_token 失效
+	generation, err := dao.GetTokenGeneration(ctx, record.UserID)
+	if err != nil {
+		span.SetStatus(codes.Error, "redis error")
+		return nil, err
+	}
+	if generation != record.Generation {
+		span.SetStatus(codes.Error, "refresh token revoked")
+		return nil, errInvalidRefreshToken
+	}
+	// 会话已被撤销
+	active, err := dao.TouchSession(ctx, record.FamilyID, time.Now())
+	if err != nil {
+		span.SetStatus(codes.Error, "redis error")
+		return nil, err
+	}
+	if !active {
+		span.SetStatus(codes.Error, "session revoked")
+		return nil, errInvalidRefreshToken
+	}
+	if err := dao.ExtendSession(ctx, record.UserID, record.FamilyID, utils.RefreshTokenTTL); err != nil {
+		span.SetStatus(codes.Error, "redis error")
+		return nil, err
+	}
+	// 重新读取用户，使角色变更在下次刷新时生效
+	user, err := model.GetUserByUserID(dao.DB, record.UserID)
+	if err != nil {
+		span.SetStatus(codes.Error, "user not found")
+		return nil, errInvalidRefreshToken
+	}
+	if user.DisabledAt != nil {
+		span.SetStatus(codes.Error, "account disabled")
+		return nil, errAccountDisabled
+	}
+	// 在同一家族中签发新的 token
+	tokens, err := issueTokenPair(ctx, user, record.FamilyID, generation)
+	if err != nil {
+		span.SetStatus(codes.Error, "generate token failed")
+		return nil, err
+	}
+	span.AddEvent("refresh token success")
+	return &pb.RefreshTokenResp{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+	}, nil
+}
+
+// Logout 退出当前登录，当前 access_token 加入黑名单，同一次登录的会话和 refresh_token 全部失效
+func (u *UserService) Logout(ctx context.Context, req *pb.LogoutReq) (*pb.LogoutResp, error) {
+	tr := otel.Tracer("user-service")
+	_, span := tr.Start(ctx, "Logout")
+	defer span.End()
+	claims, ok := claimsFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "missing user ID in context")
+		return nil, errors.New("missing user ID in context")
+	}
+	span.SetAttributes(attribute.String("user_id", claims.UserID))
+	var expiresAt time.Time
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+	if err := dao.RevokeToken(ctx, claims.ID, expiresAt); err != nil {
+		span.SetStatus(codes.Error, "revoke access token failed")
+		return nil, err
+	}
+	if claims.SessionID != "" {
+		if err := revokeSession(ctx, claims.UserID, claims.SessionID); err != nil {
+			span.SetStatus(codes.Error, "revoke session failed")
+			return nil, err
+		}
+	}
+	span.AddEvent("logout success")
+	return &pb.LogoutResp{}, nil
+}
+
+// LogoutAllSessions 退出所有登录，该用户已签发的 access_token 和 refresh_token 全部失效
+func (u *UserService) LogoutAllSessions(ctx context.Context, req *pb.LogoutAllSessionsReq) (*pb.LogoutAllSessionsResp, error) {
+	tr := otel.Tracer("user-service")
+	_, span := tr.Start(ctx, "LogoutAllSessions")
+	defer span.End()
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "missing user ID in context")
+		return nil, errors.New("missing user ID in context")
+	}
+	span.SetAttributes(attribute.String("user_id", userID))
+	if err := revokeUserTokens(ctx, userID); err != nil {
+		span.SetStatus(codes.Error, "revoke tokens failed")
+		return nil, err
+	}
+	span.AddEvent("logout all sessions success")
+	return &pb.LogoutAllSessionsResp{}, nil
+}
+
+// claimsFromContext 获取认证拦截器写入上下文的 token 信息
+func claimsFromContext(ctx context.Context) (*utils.Claims, bool) {
+	claims, ok := ctx.Value("claims").(*utils.Claims)
+	return claims, ok && claims != nil
+}
+
+// issueTokens 为新的登录创建会话并签发 access_token 和 refresh_token，refresh_token 开启一个新的家族
+func issueTokens(ctx context.Context, user *model.User) (*tokenPair, error) {
+	generation, err := dao.GetTokenGeneration(ctx, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+	session := newSession(ctx, user.UserID)
+	if err := dao.CreateSession(ctx, session, utils.RefreshTokenTTL); err != nil {
+		return nil, err
+	}
+	return issueTokenPair(ctx, user, session.ID, generation)
+}
+
+// issueTokenPair 在指定家族中签发 access_token 和 refresh_token
+func issueTokenPair(ctx context.Context, user *model.User, familyID string, generation int64) (*tokenPair, error) {
+	accessToken, err := utils.GetToken(utils.TokenInfo{
+		UserID:     user.UserID,
+		Generation: generation,
+		SessionID:  familyID,
+		Role:       user.Role,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// refresh_token 为随机字符串，服务端只保存摘要
+	refreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	err = dao.SaveRefreshToken(ctx, utils.HashToken(refreshToken), dao.RefreshToken{
+		UserID:     user.UserID,
+		FamilyID:   familyID,
+		Generation: generation,
+		ExpiresAt:  time.Now().Add(utils.RefreshTokenTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(utils.AccessTokenTTL / time.Second),
+	}, nil
+}
+
+// revokeUserTokens 使用户已签发的所有 token 失效，包括 refresh_token 和会话
+func revokeUserTokens(ctx context.Context, userID string) error {
+	if _, err := dao.BumpTokenGeneration(ctx, userID); err != nil {
+		return err
+	}
+	_, err := dao.DeleteUserSessions(ctx, userID)
+	return err
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/HCH1212/taxin/api/pb/user"
+	"github.com/HCH1212/taxin/internal/dao"
+	"github.com/HCH1212/taxin/internal/middleware"
+	"github.com/HCH1212/taxin/internal/model"
+	"github.com/HCH1212/taxin/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authenticateAccessToken 让 access_token 经过认证拦截器，返回拦截器的错误
+func authenticateAccessToken(accessToken string) error {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+accessToken))
+	info := &grpc.UnaryServerInfo{FullMethod: user.UserService_GetUserInfo_FullMethodName}
+	_, err := middleware.AuthInterceptor()(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	return err
+}
+
+// authenticatedContext 返回认证拦截器交给处理函数的上下文，其中包含 user_id 和 token 信息
+func authenticatedContext(t *testing.T, accessToken string) context.Context {
+	t.Helper()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+accessToken))
+	info := &grpc.UnaryServerInfo{FullMethod: user.UserService_GetUserInfo_FullMethodName}
+	var authed context.Context
+	_, err := middleware.AuthInterceptor()(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		authed = ctx
+		return nil, nil
+	})
+	require.NoError(t, err)
+	return authed
+}
+
+func TestLogoutRevokesAccessToken(t *testing.T) {
+	setupTestRedis(t)
+	ctx := context.Background()
+	testUser := &model.User{UserID: "user-logout", Role: model.RoleUser}
+	current, err := issueTokens(ctx, testUser)
+	require.NoError(t, err)
+	other, err := issueTokens(ctx, testUser)
+	require.NoError(t, err)
+
+	_, err = (&UserService{}).Logout(authenticatedContext(t, current.AccessToken), &user.LogoutReq{})
+	require.NoError(t, err)
+
+	// 当前 access_token 的 jti 进入黑名单，同一次登录的 refresh_token 不能再使用
+	claims, err := utils.ParseAccessToken(current.AccessToken)
+	require.NoError(t, err)
+	revoked, err := dao.IsTokenRevoked(ctx, claims.ID)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+	err = authenticateAccessToken(current.AccessToken)
+	assert.Equal(t, grpccodes.Unauthenticated, status.Code(err))
+	_, err = (&UserService{}).RefreshToken(ctx, &user.RefreshTokenReq{RefreshToken: current.RefreshToken})
+	assert.ErrorIs(t, err, errInvalidRefreshToken)
+
+	// 其他登录不受影响
+	assert.NoError(t, authenticateAccessToken(other.AccessToken))
+}
+
+func TestLogoutAllSessions(t *testing.T) {
+	setupTestRedis(t)
+	ctx := context.Background()
+	testUser := &model.User{UserID: "user-logout-all", Role: model.RoleUser}
+	first, err := issueTokens(ctx, testUser)
+	require.NoError(t, err)
+	second, err := issueTokens(ctx, testUser)
+	require.NoError(t, err)
+	otherUser, err := issueTokens(ctx, &model.User{UserID: "user-other", Role: model.RoleUser})
+	require.NoError(t, err)
+
+	_, err = (&UserService{}).LogoutAllSessions(authenticatedContext(t, first.AccessToken), &user.LogoutAllSessionsReq{})
+	require.NoError(t, err)
+
+	for _, tokens := range []*tokenPair{first, second} {
+		err = authenticateAccessToken(tokens.AccessToken)
+		assert.Equal(t, grpccodes.Unauthenticated, status.Code(err))
+		_, err = (&UserService{}).RefreshToken(ctx, &user.RefreshTokenReq{RefreshToken: tokens.RefreshToken})
+		assert.ErrorIs(t, err, errInvalidRefreshToken)
+	}
+	sessions, err := dao.ListSessions(ctx, "user-logout-all")
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+	assert.NoError(t, authenticateAccessToken(otherUser.AccessToken))
+
+	// 之后的登录不受影响
+	next, err := issueTokens(ctx, testUser)
+	require.NoError(t, err)
+	assert.NoError(t, authenticateAccessToken(next.AccessToken))
+}
+
+// 刷新时轮换 refresh_token，旧的 refresh_token 再次使用时作废整个家族
+func TestRefreshTokenRotation(t *testing.T) {
+	setupTestRedis(t)
+	setupTestDB(t)
+	testUser := createTestUser(t, "user-rotate", "rotate", "secret")
+	ctx := context.Background()
+	u := &UserService{}
+
+	tokens, err := issueTokens(ctx, testUser)
+	require.NoError(t, err)
+	rotated, err := u.RefreshToken(ctx, &user.RefreshTokenReq{RefreshToken: tokens.RefreshToken})
+	require.NoError(t, err)
+	assert.NotEqual(t, tokens.RefreshToken, rotated.RefreshToken)
+	require.NoError(t, authenticateAccessToken(rotated.AccessToken))
+
+	// 旧的 refresh_token 被重放，新旧 token 全部失效
+	_, err = u.RefreshToken(ctx, &user.RefreshTokenReq{RefreshToken: tokens.RefreshToken})
+	assert.ErrorIs(t, err, errInvalidRefreshToken)
+	_, err = u.RefreshToken(ctx, &user.RefreshTokenReq{RefreshToken: rotated.RefreshToken})
+	assert.ErrorIs(t, err, errInvalidRefreshToken)
+	err = authenticateAccessToken(rotated.AccessToken)
+	assert.Equal(t, grpccodes.Unauthenticated, status.Code(err))
+}
+
+func TestRevokeSession(t *testing.T) {
+	setupTestRedis(t)
+	ctx := context.Background()
+	testUser := &model.User{UserID: "user-sessions", Role: model.RoleUser}
+	current, err := issueTokens(ctx, testUser)
+	require.NoError(t, err)
+	other, err := issueTokens(ctx, testUser)
+	require.NoError(t, err)
+	stranger, err := issueTokens(ctx, &model.User{UserID: "user-stranger", Role: model.RoleUser})
+	require.NoError(t, err)
+	authed := authenticatedContext(t, current.AccessToken)
+	u := &UserService{}
+
+	resp, err := u.ListSessions(authed, &user.ListSessionsReq{})
+	require.NoError(t, err)
+	require.Len(t, resp.Sessions, 2)
+	otherClaims, err := utils.ParseAccessToken(other.AccessToken)
+	require.NoError(t, err)
+
+	// 不能撤销其他用户的会话
+	strangerClaims, err := utils.ParseAccessToken(stranger.AccessToken)
+	require.NoError(t, err)
+	_, err = u.RevokeSession(authed, &user.RevokeSessionReq{SessionId: strangerClaims.SessionID})
+	assert.Equal(t, errSessionNotFound, err)
+	assert.NoError(t, authenticateAccessToken(stranger.AccessToken))
+
+	// 撤销后该设备的 token 立即失效，当前设备不受影响
+	_, err = u.RevokeSession(authed, &user.RevokeSessionReq{SessionId: otherClaims.SessionID})
+	require.NoError(t, err)
+	err = authenticateAccessToken(other.AccessToken)
+	assert.Equal(t, grpccodes.Unauthenticated, status.Code(err))
+	_, err = u.RefreshToken(ctx, &user.RefreshTokenReq{RefreshToken: other.RefreshToken})
+	assert.ErrorIs(t, err, errInvalidRefreshToken)
+	assert.NoError(t, authenticateAccessToken(current.AccessToken))
+	resp, err = u.ListSessions(authed, &user.ListSessionsReq{})
+	require.NoError(t, err)
+	assert.Len(t, resp.Sessions, 1)
+}
+
+func TestRefreshTokenReuseRevokesSession(t *testing.T) {
+	setupTestRedis(t)
+	ctx := context.Background()
+	tokens, err := issueTokens(ctx, &model.User{UserID: "user-1", Role: model.RoleUser})
+	require.NoError(t, err)
+	require.NoError(t, authenticateAccessToken(tokens.AccessToken))
+
+	// refresh_token 已被轮换过一次，再次出现视为重放
+	record, err := dao.GetRefreshToken(ctx, utils.HashToken(tokens.RefreshToken))
+	require.NoError(t, err)
+	first, err := dao.MarkRefreshTokenUsed(ctx, utils.HashToken(tokens.RefreshToken), record.ExpiresAt)
+	require.NoError(t, err)
+	require.True(t, first)
+
+	_, err = (&UserService{}).RefreshToken(ctx, &user.RefreshTokenReq{RefreshToken: tokens.RefreshToken})
+	assert.ErrorIs(t, err, errInvalidRefreshToken)
+
+	// 该家族已签发的 access_token 立即失效
+	err = authenticateAccessToken(tokens.AccessToken)
+	assert.Equal(t, grpccodes.Unauthenticated, status.Code(err))
+	revoked, err := dao.IsRefreshFamilyRevoked(ctx, record.FamilyID)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+}
@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	pb "github.com/HCH1212/taxin/api/pb/user"
+	"github.com/HCH1212/taxin/config"
+	"github.com/HCH1212/taxin/internal/dao"
+	"github.com/HCH1212/taxin/internal/model"
+	"github.com/HCH1212/taxin/internal/utils"
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+// 未配置时两步验证的默认参数
+const (
+	defaultTOTPIssuer       = "taxin"
+	defaultTOTPEnrollTTL    = 10 * time.Minute
+	defaultTOTPChallengeTTL = 5 * time.Minute
+	defaultTOTPMaxAttempts  = 5
+	recoveryCodeCount       = 10
+)
+
+var (
+	// errInvalidTOTPChallenge 登录挑战不存在、已过期或尝试次数已用尽
+	errInvalidTOTPChallenge = errors.New("invalid or expired challenge")
+	// errInvalidTOTPCode 验证码或恢复码错误
+	errInvalidTOTPCode = errors.New("invalid code")
+)
+
+// EnrollTOTP 为当前用户生成两步验证密钥，需要使用验证码调用 ConfirmTOTP 后才会生效
+func (u *UserService) EnrollTOTP(ctx context.Context, req *pb.EnrollTOTPReq) (*pb.EnrollTOTPResp, error) {
+	tr := otel.Tracer("user-service")
+	_, span := tr.Start(ctx, "EnrollTOTP")
+	defer span.End()
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "missing user ID in context")
+		return nil, errors.New("missing user ID in context")
+	}
+	span.SetAttributes(attribute.String("user_id", userID))
+	user, err := model.GetUserByUserID(dao.DB, userID)
+	if err != nil {
+		span.SetStatus(codes.Error, "user not found")
+		return nil, err
+	}
+	if user.TOTPSecret != "" {
+		span.SetStatus(codes.Error, "totp already enabled")
+		return nil, errors.New("totp already enabled")
+	}
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		span.SetStatus(codes.Error, "generate totp secret failed")
+		return nil, err
+	}
+	conf := totpConf()
+	if err := dao.SavePendingTOTP(ctx, userID, secret, conf.EnrollTTL); err != nil {
+		span.SetStatus(codes.Error, "store totp secret failed")
+		return nil, err
+	}
+	account := user.Username
+	if account == "" {
+		account = user.UserID
+	}
+	span.AddEvent("enroll totp success")
+	return &pb.EnrollTOTPResp{
+		Secret:     secret,
+		OtpauthUri: utils.TOTPURI(conf.Issuer, account, secret),
+	}, nil
+}
+
+// ConfirmTOTP 校验验证码后开启两步验证，并返回一次性恢复码
+func (u *UserService) ConfirmTOTP(ctx context.Context, req *pb.ConfirmTOTPReq) (*pb.ConfirmTOTPResp, error) {
+	tr := otel.Tracer("user-service")
+	_, span := tr.Start(ctx, "ConfirmTOTP")
+	defer span.End()
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "missing user ID in context")
+		return nil, errors.New("missing user ID in context")
+	}
+	span.SetAttributes(attribute.String("user_id", userID))
+	// 参数校验
+	if req.Code == "" {
+		span.SetStatus(codes.Error, "invalid request")
+		return nil, errors.New("invalid request")
+	}
+	secret, err := dao.GetPendingTOTP(ctx, userID)
+	if err == redis.Nil {
+		span.SetStatus(codes.Error, "no pending totp enrollment")
+		return nil, errors.New("no pending totp enrollment")
+	} else if err != nil {
+		span.SetStatus(codes.Error, "redis error")
+		return nil, err
+	}
+	if err := checkTOTPCode(ctx, userID, secret, req.Code); err != nil {
+		span.SetStatus(codes.Error, "invalid totp code")
+		return nil, err
+	}
+	// 生成恢复码，只保存哈希
+	recoveryCodes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			span.SetStatus(codes.Error, "generate recovery code failed")
+			return nil, err
+		}
+		hash, err := utils.HashPassword(normalizeRecoveryCode(code))
+		if err != nil {
+			span.SetStatus(codes.Error, "hash recovery code failed")
+			return nil, err
+		}
+		recoveryCodes = append(recoveryCodes, code)
+		hashes = append(hashes, hash)
+	}
+	// 密钥加密后保存
+	encrypted, err := utils.EncryptTOTPSecret(userID, secret)
+	if err != nil {
+		span.SetStatus(codes.Error, "encrypt totp secret failed")
+		return nil, err
+	}
+	err = dao.DB.Transaction(func(tx *gorm.DB) error {
+		if err := model.UpdateUser(tx, userID, map[string]interface{}{"totp_secret": encrypted}); err != nil {
+			return err
+		}
+		return model.ReplaceRecoveryCodes(tx, userID, hashes)
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, "enable totp failed")
+		return nil, err
+	}
+	if err := dao.DeletePendingTOTP(ctx, userID); err != nil {
+		span.SetStatus(codes.Error, "redis error")
+		return nil, err
+	}
+	span.AddEvent("confirm totp success")
+	return &pb.ConfirmTOTPResp{RecoveryCodes: recoveryCodes}, nil
+}
+
+// VerifyTOTP 使用登录返回的挑战和验证码（或恢复码）完成登录
+func (u *UserService) VerifyTOTP(ctx context.Context, req *pb.VerifyTOTPReq) (*pb.LoginResp, error) {
+	tr := otel.Tracer("user-service")
+	_, span := tr.Start(ctx, "VerifyTOTP")
+	defer span.End()
+	// 参数校验
+	if req.ChallengeToken == "" || req.Code == "" {
+		span.SetStatus(codes.Error, "invalid request")
+		return nil, errors.New("invalid request")
+	}
+	challengeHash := utils.HashToken(req.ChallengeToken)
+	userID, attempts, err := dao.AttemptTOTPChallenge(ctx, challengeHash)
+	if err == redis.Nil {
+		span.SetStatus(codes.Error, "challenge not found")
+		return nil, errInvalidTOTPChallenge
+	} else if err != nil {
+		span.SetStatus(codes.Error, "redis error")
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("user_id", userID))
+	// 尝试次数用尽后作废挑战，需要重新使用密码登录
+	if attempts > int64(totpConf().MaxAttempts) {
+		if err := dao.DeleteTOTPChallenge(ctx, challengeHash); err != nil {
+			span.SetStatus(codes.Error, "redis error")
+			return nil, err
+		}
+		span.SetStatus(codes.Error, "too many attempts")
+		return nil, errInvalidTOTPChallenge
+	}
+	user, err := model.GetUserByUserID(dao.DB, userID)
+	if err != nil {
+		span.SetStatus(codes.Error, "user not found")
+		return nil, err
+	}
+	if user.TOTPSecret == "" {
+		span.SetStatus(codes.Error, "totp not enabled")
+		return nil, errInvalidTOTPChallenge
+	}
+	if user.DisabledAt != nil {
+		span.SetStatus(codes.Error, "account disabled")
+		return nil, errAccountDisabled
+	}
+	secret, err := utils.DecryptTOTPSecret(userID, user.TOTPSecret)
+	if err != nil {
+		span.SetStatus(codes.Error, "decrypt totp secret failed")
+		return nil, err
+	}
+	if isTOTPCode(req.Code) {
+		err = checkTOTPCode(ctx, userID, secret, req.Code)
+	} else {
+		err = useRecoveryCode(userID, req.Code)
+		if err == nil {
+			span.AddEvent("recovery code used")
+		}
+	}
+	if err != nil {
+		span.SetStatus(codes.Error, "invalid totp code")
+		return nil, err
+	}
+	if err := dao.DeleteTOTPChallenge(ctx, challengeHash); err != nil {
+		span.SetStatus(codes.Error, "redis error")
+		return nil, err
+	}
+	// 加密前保存的明文密钥在验证成功后改为加密保存，失败不影响登录
+	if !utils.IsEncryptedTOTPSecret(user.TOTPSecret) {
+		if err := encryptStoredTOTPSecret(userID, secret); err != nil {
+			log.Printf("totp: encrypt stored secret for user %s: %v", userID, err)
+		} else {
+			span.AddEvent("totp secret encrypted")
+		}
+	}
+	tokens, err := issueTokens(ctx, user)
+	if err != nil {
+		span.SetStatus(codes.Error, "generate access token failed")
+		return nil, err
+	}
+	span.AddEvent("verify totp success")
+	return &pb.LoginResp{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+	}, nil
+}
+
+// startTOTPChallenge 密码校验通过后为开启了两步验证的用户生成登录挑战
+func startTOTPChallenge(ctx context.Context, userID string) (string, error) {
+	challenge, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	if err := dao.SaveTOTPChallenge(ctx, utils.HashToken(challenge), userID, totpConf().ChallengeTTL); err != nil {
+		return "", err
+	}
+	return challenge, nil
+}
+
+// encryptStoredTOTPSecret 加密并保存用户的两步验证密钥，只更新仍为明文的记录
+func encryptStoredTOTPSecret(userID, secret string) error {
+	encrypted, err := utils.EncryptTOTPSecret(userID, secret)
+	if err != nil {
+		return err
+	}
+	return dao.DB.Model(&model.User{}).
+		Where("user_id = ? AND totp_secret = ?", userID, secret).
+		Update("totp_secret", encrypted).Error
+}
+
+// checkTOTPCode 校验验证码，同一验证码只能使用一次
+func checkTOTPCode(ctx context.Context, userID, secret, code string) error {
+	step, ok := utils.VerifyTOTP(secret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return errInvalidTOTPCode
+	}
+	// 验证码在允许偏差的时间窗口内都有效，记录到窗口结束为止
+	ttl := time.Duration(2*utils.TOTPSkew+1) * utils.TOTPPeriod * time.Second
+	first, err := dao.MarkTOTPStepUsed(ctx, userID, step, ttl)
+	if err != nil {
+		return err
+	}
+	if !first {
+		return errInvalidTOTPCode
+	}
+	return nil
+}
+
+// useRecoveryCode 校验并消耗一个恢复码
+func useRecoveryCode(userID, code string) error {
+	code = normalizeRecoveryCode(code)
+	recoveryCodes, err := model.ListUnusedRecoveryCodes(dao.DB, userID)
+	if err != nil {
+		return err
+	}
+	for _, rc := range recoveryCodes {
+		if !utils.VerifyPassword(rc.CodeHash, code) {
+			continue
+		}
+		used, err := model.UseRecoveryCode(dao.DB, rc.ID)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return errInvalidTOTPCode
+		}
+		return nil
+	}
+	return errInvalidTOTPCode
+}
+
+// isTOTPCode 判断输入是验证码还是恢复码
+func isTOTPCode(code string) bool {
+	code = strings.TrimSpace(code)
+	if len(code) != utils.TOTPDigits {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// generateRecoveryCode 生成形如 abcd-efgh 的恢复码
+func generateRecoveryCode() (string, error) {
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return "", err
+	}
+	code := strings.ToLower(secret[:8])
+	return code[:4] + "-" + code[4:], nil
+}
+
+// normalizeRecoveryCode 忽略大小写、空格和分隔符
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// totpConf 读取两步验证配置，未配置的项使用默认值
+func totpConf() config.TOTP {
+	conf := config.GetConf().TOTP
+	if conf.Issuer == "" {
+		conf.Issuer = defaultTOTPIssuer
+	}
+	if conf.EnrollTTL <= 0 {
+		conf.EnrollTTL = defaultTOTPEnrollTTL
+	}
+	if conf.ChallengeTTL <= 0 {
+		conf.ChallengeTTL = defaultTOTPChallengeTTL
+	}
+	if conf.MaxAttempts <= 0 {
+		conf.MaxAttempts = defaultTOTPMaxAttempts
+	}
+	return conf
+}
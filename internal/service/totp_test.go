@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/HCH1212/taxin/api/pb/user"
+	"github.com/HCH1212/taxin/internal/dao"
+	"github.com/HCH1212/taxin/internal/model"
+	"github.com/HCH1212/taxin/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// currentTOTPCode 计算密钥当前的验证码
+func currentTOTPCode(t *testing.T, secret string) string {
+	t.Helper()
+	code, err := utils.TOTPCode(secret, utils.TOTPStep(time.Now()))
+	require.NoError(t, err)
+	return code
+}
+
+func TestConfirmTOTPEncryptsSecret(t *testing.T) {
+	setupTestRedis(t)
+	setupTestDB(t)
+	utils.InitTOTP()
+	createTestUser(t, "user-totp", "totp", "secret")
+	ctx := context.WithValue(context.Background(), "user_id", "user-totp")
+	u := &UserService{}
+
+	enroll, err := u.EnrollTOTP(ctx, &pb.EnrollTOTPReq{})
+	require.NoError(t, err)
+	_, err = u.ConfirmTOTP(ctx, &pb.ConfirmTOTPReq{Code: currentTOTPCode(t, enroll.Secret)})
+	require.NoError(t, err)
+
+	// 数据库中只保存密文
+	user, err := model.GetUserByUserID(dao.DB, "user-totp")
+	require.NoError(t, err)
+	assert.True(t, utils.IsEncryptedTOTPSecret(user.TOTPSecret))
+	assert.NotContains(t, user.TOTPSecret, enroll.Secret)
+	secret, err := utils.DecryptTOTPSecret("user-totp", user.TOTPSecret)
+	require.NoError(t, err)
+	assert.Equal(t, enroll.Secret, secret)
+}
+
+// 加密前保存的明文密钥仍可以登录，登录成功后改为加密保存
+func TestVerifyTOTPEncryptsLegacySecret(t *testing.T) {
+	setupTestRedis(t)
+	setupTestDB(t)
+	utils.InitTOTP()
+	createTestUser(t, "user-legacy", "legacy", "secret")
+	secret, err := utils.GenerateTOTPSecret()
+	require.NoError(t, err)
+	require.NoError(t, model.UpdateUser(dao.DB, "user-legacy", map[string]interface{}{"totp_secret": secret}))
+	ctx := context.Background()
+	u := &UserService{}
+
+	login, err := u.Login(ctx, &pb.LoginReq{Username: "legacy", Password: "secret"})
+	require.NoError(t, err)
+	require.True(t, login.TotpRequired)
+	_, err = u.VerifyTOTP(ctx, &pb.VerifyTOTPReq{ChallengeToken: login.ChallengeToken, Code: currentTOTPCode(t, secret)})
+	require.NoError(t, err)
+
+	user, err := model.GetUserByUserID(dao.DB, "user-legacy")
+	require.NoError(t, err)
+	assert.True(t, utils.IsEncryptedTOTPSecret(user.TOTPSecret))
+	decrypted, err := utils.DecryptTOTPSecret("user-legacy", user.TOTPSecret)
+	require.NoError(t, err)
+	assert.Equal(t, secret, decrypted)
+}
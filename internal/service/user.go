@@ -2,26 +2,156 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"log"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	pb "github.com/HCH1212/taxin/api/pb/user"
+	"github.com/HCH1212/taxin/config"
 	"github.com/HCH1212/taxin/internal/dao"
 	"github.com/HCH1212/taxin/internal/model"
 	"github.com/HCH1212/taxin/internal/utils"
-	"github.com/go-redis/redis/v8"
+	"github.com/pgvector/pgvector-go"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
+const (
+	defaultSimilarLimit = 10  // FindSimilarUsers 默认返回数量
+	maxSimilarLimit     = 100 // FindSimilarUsers 最大返回数量
+	defaultPageSize     = 10  // 分页查询默认每页数量
+	maxPageSize         = 100 // 分页查询最大每页数量
+)
+
 type UserService struct {
 	pb.UnimplementedUserServiceServer
 }
 
+const (
+	registerRedisTTL = time.Hour * 24   // 注册幂等键的有效期
+	registerLockTTL  = time.Second * 30 // 注册锁的有效期，需大于同步生成向量的耗时
+)
+
+// errUsernameTaken 用户名已被占用，注册时表示已注册用户的密码或喜好与本次请求不一致
+var errUsernameTaken = status.Error(grpccodes.AlreadyExists, "username already exists")
+
+// registerRedisKey 注册幂等键，值为用户名对应的用户 ID
+func registerRedisKey(username string) string {
+	return "register:redis:" + username
+}
+
+// registeredUserIDs 查询用户名是否已注册，先查注册幂等键再查数据库，返回已注册的用户名到 user_id 的映射
+func registeredUserIDs(ctx context.Context, usernames []string) (map[string]string, error) {
+	ids := make(map[string]string, len(usernames))
+	if len(usernames) == 0 {
+		return ids, nil
+	}
+	keys := make([]string, len(usernames))
+	for i, username := range usernames {
+		keys[i] = registerRedisKey(username)
+	}
+	values, err := dao.RedisClient.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for i, v := range values {
+		if userID, ok := v.(string); ok {
+			ids[usernames[i]] = userID
+		} else {
+			missing = append(missing, usernames[i])
+		}
+	}
+	if len(missing) == 0 {
+		return ids, nil
+	}
+	found, err := model.GetUserIDsByUsernames(dao.DB.WithContext(ctx), missing)
+	if err != nil {
+		return nil, err
+	}
+	for username, userID := range found {
+		ids[username] = userID
+	}
+	return ids, nil
+}
+
+// checkRegisterRepeat 检查用户名是否已注册，未注册时返回空
+// 已注册时比较请求指纹：密码（或密码哈希）和喜好都一致才视为重复请求返回已有的 user_id，否则返回 errUsernameTaken
+func checkRegisterRepeat(ctx context.Context, username, password, passwordHash string, like []string) (string, error) {
+	registered, err := registeredUserIDs(ctx, []string{username})
+	if err != nil {
+		return "", err
+	}
+	userID, ok := registered[username]
+	if !ok {
+		return "", nil
+	}
+	user, err := model.GetUserByUserID(dao.DB.WithContext(ctx), userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// 幂等键对应的用户已不存在，按未注册处理，仍被占用时由唯一约束拦截
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	if !registerFingerprintMatches(user, password, passwordHash, like) {
+		return "", errUsernameTaken
+	}
+	return userID, nil
+}
+
+// registerFingerprintMatches 请求的密码（或密码哈希）和喜好是否与已注册的用户一致
+func registerFingerprintMatches(user *model.User, password, passwordHash string, like []string) bool {
+	if !slices.Equal(user.GetLikeList(), like) {
+		return false
+	}
+	if passwordHash != "" {
+		return user.Password == passwordHash
+	}
+	return utils.VerifyPassword(user.Password, password)
+}
+
+// registerRepeatResp 根据 checkRegisterRepeat 的结果返回重复注册的响应
+func registerRepeatResp(span trace.Span, userID string, err error) (*pb.RegisterResp, error) {
+	if errors.Is(err, errUsernameTaken) {
+		span.SetStatus(codes.Error, "username already exists")
+		return nil, err
+	}
+	if err != nil {
+		span.SetStatus(codes.Error, "query register info failed")
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("user_id", userID))
+	span.AddEvent("register repeated")
+	return &pb.RegisterResp{UserId: userID}, nil
+}
+
+// errInvalidCredentials 账号不存在和密码错误返回同样的错误，避免枚举账号
+var errInvalidCredentials = errors.New("invalid credentials")
+
+// dummyPasswordHash 账号不存在时用于比较的密码哈希，首次使用时生成
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, _ := utils.HashPassword("taxin-dummy-password")
+	return hash
+})
+
+// userIDFromContext 获取认证拦截器写入上下文的用户 ID
+func userIDFromContext(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value("user_id").(string)
+	return userID, ok && userID != ""
+}
+
 // Register 注册新用户
 func (u *UserService) Register(ctx context.Context, req *pb.RegisterReq) (*pb.RegisterResp, error) {
 	// 创建一个新的 span
@@ -33,22 +163,28 @@ func (u *UserService) Register(ctx context.Context, req *pb.RegisterReq) (*pb.Re
 		span.SetStatus(codes.Error, "invalid request")
 		return nil, errors.New("invalid request")
 	}
-	// 注册幂等性校验
-	redisKey := "register:redis:" + req.Username
-	if userID, err := dao.RedisClient.Get(ctx, redisKey).Result(); err == nil {
-		span.AddEvent("register success")
-		return &pb.RegisterResp{UserId: userID}, nil
-	} else if err != redis.Nil {
+	// 注册幂等性校验，用户名已注册且密码和喜好一致时视为重复请求，直接返回已有的 user_id
+	if userID, err := checkRegisterRepeat(ctx, req.Username, req.Password, "", req.Like); err != nil || userID != "" {
+		return registerRepeatResp(span, userID, err)
+	}
+	// 同一用户名同时只允许一个请求注册，获取锁后再检查一次，避免并发请求都认为用户名未注册
+	lockToken := utils.GenerateUUID()
+	locked, err := dao.AcquireRegisterLock(ctx, req.Username, lockToken, registerLockTTL)
+	if err != nil {
 		span.SetStatus(codes.Error, "redis error")
 		return nil, err
 	}
-	userID, err := model.GetUserIDByUsername(dao.DB, req.Username)
-	if err == nil {
-		span.AddEvent("register success")
-		return &pb.RegisterResp{UserId: userID}, nil
-	} else if err != gorm.ErrRecordNotFound {
-		span.SetStatus(codes.Error, "database error")
-		return nil, err
+	if !locked {
+		span.SetStatus(codes.Error, "register in progress")
+		return nil, retryError(grpccodes.Aborted, "registration in progress", time.Second)
+	}
+	defer func() {
+		if err := dao.ReleaseRegisterLock(context.WithoutCancel(ctx), req.Username, lockToken); err != nil {
+			log.Printf("register: release lock for %s: %v", req.Username, err)
+		}
+	}()
+	if userID, err := checkRegisterRepeat(ctx, req.Username, req.Password, "", req.Like); err != nil || userID != "" {
+		return registerRepeatResp(span, userID, err)
 	}
 	// 以下开始注册新用户
 	// 密码加密
@@ -57,14 +193,8 @@ func (u *UserService) Register(ctx context.Context, req *pb.RegisterReq) (*pb.Re
 		span.SetStatus(codes.Error, "hash password failed")
 		return nil, err
 	}
-	// 生成词嵌入向量
-	embedding, err := utils.GenerateEmbeddingForLikes(ctx, req.Like)
-	if err != nil {
-		span.SetStatus(codes.Error, "generate embedding failed")
-		return nil, err
-	}
 	// 生成用户ID并创建用户
-	userID = utils.GenerateUUID()
+	userID := utils.GenerateUUID()
 	span.SetAttributes(attribute.String("user_id", userID))
 	// 爱好转json
 	likeJSON, err := json.Marshal(req.Like)
@@ -73,21 +203,59 @@ func (u *UserService) Register(ctx context.Context, req *pb.RegisterReq) (*pb.Re
 		return nil, err
 	}
 	user := model.User{
-		Username:      req.Username,
-		UserID:        userID,
-		Password:      hashPassword,
-		Like:          datatypes.JSON(likeJSON),
-		LikeEmbedding: embedding,
+		Username:        req.Username,
+		UserID:          userID,
+		Password:        hashPassword,
+		Like:            datatypes.JSON(likeJSON),
+		EmbeddingStatus: model.EmbeddingStatusPending,
+	}
+	// 同步模式下直接生成词嵌入向量，异步模式下由后台任务生成，注册不依赖向量服务是否可用
+	async := config.GetConf().Embedding.Async
+	var userLikes []model.UserLike
+	if !async {
+		// 为每个喜好生成词嵌入向量，并聚合为用户画像向量
+		likeEmbeddings, err := utils.GenerateLikeEmbeddings(ctx, req.Like)
+		if err != nil {
+			span.SetStatus(codes.Error, "generate embedding failed")
+			return nil, err
+		}
+		embedding, err := utils.ProfileEmbedding(likeEmbeddings)
+		if err != nil {
+			span.SetStatus(codes.Error, "generate embedding failed")
+			return nil, err
+		}
+		user.LikeEmbedding = &embedding
+		user.EmbeddingStatus = model.EmbeddingStatusReady
+		user.EmbeddingModel = utils.EmbeddingModel()
+		user.EmbeddingDim = len(embedding.Slice())
+		userLikes = model.NewUserLikes(userID, req.Like, likeEmbeddings, user.EmbeddingModel)
 	}
 	// 先操作数据库再操作redis，防止出现数据不一致的情况
-	// 存储用户信息到数据库
-	err = model.CreateUser(dao.DB, &user)
+	// 存储用户信息到数据库，同时写入每个喜好的向量或异步生成任务
+	err = dao.DB.Transaction(func(tx *gorm.DB) error {
+		if err := model.CreateUser(tx, &user); err != nil {
+			return err
+		}
+		if async {
+			return model.CreateEmbeddingJob(tx, userID)
+		}
+		return model.CreateUserLikes(tx, userLikes)
+	})
+	// 锁过期等情况下仍可能并发写入同一用户名，以数据库唯一约束为准
+	if model.IsUniqueViolation(err) {
+		userID, err := checkRegisterRepeat(ctx, req.Username, req.Password, "", req.Like)
+		if err == nil && userID == "" {
+			// 用户名被已注销但仍在宽限期内的账号占用
+			err = errUsernameTaken
+		}
+		return registerRepeatResp(span, userID, err)
+	}
 	if err != nil {
 		span.SetStatus(codes.Error, "create user failed")
 		return nil, err
 	}
 	// 存储注册信息到redis
-	err = dao.RedisClient.Set(ctx, redisKey, userID, time.Hour*24).Err()
+	err = dao.RedisClient.Set(ctx, registerRedisKey(req.Username), userID, registerRedisTTL).Err()
 	if err != nil {
 		span.SetStatus(codes.Error, "store register info to redis failed")
 		return nil, err
@@ -102,27 +270,63 @@ func (u *UserService) Login(ctx context.Context, req *pb.LoginReq) (*pb.LoginRes
 	tr := otel.Tracer("user-service")
 	_, span := tr.Start(ctx, "Login")
 	defer span.End()
-	// 添加自定义标签
-	span.SetAttributes(attribute.String("user_id", req.UserId))
-
-	// 参数校验
-	if req.UserId == "" || req.Password == "" {
+	// 参数校验，user_id 和 username 只能提供一个
+	if (req.UserId == "") == (req.Username == "") || req.Password == "" {
 		span.SetStatus(codes.Error, "invalid request")
 		return nil, errors.New("invalid request")
 	}
 	// 查询用户信息
-	user, err := model.GetUserByUserID(dao.DB, req.UserId)
-	if err != nil {
-		span.SetStatus(codes.Error, "user not found")
+	user, err := findLoginUser(req)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		span.SetStatus(codes.Error, "query user failed")
+		return nil, err
+	}
+	var userID string
+	if user != nil {
+		userID = user.UserID
+		span.SetAttributes(attribute.String("user_id", userID))
+	}
+	// 账号被锁定、IP 被限流或失败后等待时间未到时直接拒绝
+	account := loginAccountKey(user, req.UserId, req.Username)
+	ip := clientIP(ctx)
+	if err := checkLoginAllowed(ctx, account, ip); err != nil {
+		span.SetStatus(codes.Error, "login rejected by rate limit")
 		return nil, err
 	}
-	// 验证密码
-	if !utils.VerifyPassword(user.Password, req.Password) {
-		span.SetStatus(codes.Error, "invalid password")
-		return nil, errors.New("invalid password")
+	// 验证密码，用户不存在时也做一次密码比较，使耗时与密码错误一致，避免通过响应区分账号是否存在
+	passwordHash := dummyPasswordHash()
+	if user != nil {
+		passwordHash = user.Password
 	}
-	// 生成 access_token
-	accessToken, err := utils.GetToken(req.UserId)
+	if !utils.VerifyPassword(passwordHash, req.Password) || user == nil {
+		if err := recordLoginFailure(ctx, span, account, userID, ip); err != nil {
+			span.SetStatus(codes.Error, "record login failure failed")
+			return nil, err
+		}
+		span.SetStatus(codes.Error, "invalid credentials")
+		return nil, errInvalidCredentials
+	}
+	if err := dao.ClearLoginFailures(ctx, account); err != nil {
+		span.SetStatus(codes.Error, "redis error")
+		return nil, err
+	}
+	// 被禁用的账号不能登录
+	if user.DisabledAt != nil {
+		span.SetStatus(codes.Error, "account disabled")
+		return nil, errAccountDisabled
+	}
+	// 开启了两步验证的用户需要再调用 VerifyTOTP 才能拿到 token
+	if user.TOTPSecret != "" {
+		challenge, err := startTOTPChallenge(ctx, user.UserID)
+		if err != nil {
+			span.SetStatus(codes.Error, "create totp challenge failed")
+			return nil, err
+		}
+		span.AddEvent("totp required")
+		return &pb.LoginResp{TotpRequired: true, ChallengeToken: challenge}, nil
+	}
+	// 生成 access_token 和 refresh_token
+	tokens, err := issueTokens(ctx, user)
 	if err != nil {
 		span.SetStatus(codes.Error, "generate access token failed")
 		return nil, err
@@ -131,7 +335,16 @@ func (u *UserService) Login(ctx context.Context, req *pb.LoginReq) (*pb.LoginRes
 	// 添加自定义事件
 	span.AddEvent("login success")
 
-	return &pb.LoginResp{AccessToken: accessToken}, nil
+	return &pb.LoginResp{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+	}, nil
+}
+
+// findLoginUser 按 user_id 或 username 查找登录用户，两种方式都只查询一次，耗时一致
+func findLoginUser(req *pb.LoginReq) (*model.User, error) {
+	return model.GetUser(dao.DB, req.UserId, req.Username)
 }
 
 // GetUserInfo 获取用户信息
@@ -140,27 +353,367 @@ func (u *UserService) GetUserInfo(ctx context.Context, req *pb.UserInfoReq) (*pb
 	_, span := tr.Start(ctx, "GetUserInfo")
 	defer span.End()
 	// 从上下文中获取用户 ID
-	userID := ctx.Value("user_id")
-	span.SetAttributes(attribute.String("user_id", userID.(string)))
-	if userID == nil {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
 		span.SetStatus(codes.Error, "missing user ID in context")
 		return nil, errors.New("missing user ID in context")
 	}
+	span.SetAttributes(attribute.String("user_id", userID))
 	// 查询用户信息
-	user, err := model.GetUserByUserID(dao.DB, userID.(string))
+	user, err := model.GetUserByUserID(dao.DB, userID)
 	if err != nil {
 		span.SetStatus(codes.Error, "get user failed")
 		return nil, err
 	}
 	// 组装响应
-	likeEmbedding := make([]float32, 1536)
-	copy(likeEmbedding, user.LikeEmbedding.Slice())
-	return &pb.UserInfoResp{
-		UserId:        user.UserID,
-		Like:          user.GetLikeList(),
-		LikeEmbedding: likeEmbedding,
-		CreateAt:      user.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdateAt:      user.UpdatedAt.Format("2006-01-02 15:04:05"),
-		Username:      user.Username,
-	}, nil
+	return toUserInfoResp(user), nil
+}
+
+// UpdateProfile 按 update_mask 修改用户名和喜好，修改喜好后重新生成词嵌入向量
+func (u *UserService) UpdateProfile(ctx context.Context, req *pb.UpdateProfileReq) (*pb.UserInfoResp, error) {
+	tr := otel.Tracer("user-service")
+	_, span := tr.Start(ctx, "UpdateProfile")
+	defer span.End()
+	// 从上下文中获取用户 ID
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "missing user ID in context")
+		return nil, errors.New("missing user ID in context")
+	}
+	span.SetAttributes(attribute.String("user_id", userID))
+	// 参数校验
+	mask := req.GetUpdateMask()
+	if len(mask.GetPaths()) == 0 || !mask.IsValid(req) {
+		span.SetStatus(codes.Error, "invalid update mask")
+		return nil, errors.New("invalid update mask")
+	}
+	mask.Normalize()
+	var updateUsername, updateLike bool
+	for _, path := range mask.GetPaths() {
+		switch path {
+		case "username":
+			updateUsername = true
+		case "like":
+			updateLike = true
+		default:
+			span.SetStatus(codes.Error, "invalid update mask")
+			return nil, errors.New("field " + path + " cannot be updated")
+		}
+	}
+	username := strings.TrimSpace(req.Username)
+	if (updateUsername && username == "") || (updateLike && len(req.Like) == 0) {
+		span.SetStatus(codes.Error, "invalid request")
+		return nil, errors.New("invalid request")
+	}
+	// 查询用户信息
+	user, err := model.GetUserByUserID(dao.DB, userID)
+	if err != nil {
+		span.SetStatus(codes.Error, "get user failed")
+		return nil, err
+	}
+	oldUsername := user.Username
+	updates := map[string]interface{}{}
+	if updateUsername && username != oldUsername {
+		// 用户名需要唯一
+		if _, err := model.GetUserIDByUsername(dao.DB, username); err == nil {
+			span.SetStatus(codes.Error, "username already exists")
+			return nil, errUsernameTaken
+		} else if err != gorm.ErrRecordNotFound {
+			span.SetStatus(codes.Error, "database error")
+			return nil, err
+		}
+		updates["username"] = username
+	}
+	var userLikes []model.UserLike
+	async := config.GetConf().Embedding.Async
+	if updateLike {
+		likeJSON, err := json.Marshal(req.Like)
+		if err != nil {
+			span.SetStatus(codes.Error, "marshal like failed")
+			return nil, err
+		}
+		updates["like"] = datatypes.JSON(likeJSON)
+		if async {
+			// 由后台任务重新生成，生成完成前兴趣推荐不可用
+			updates["embedding_status"] = model.EmbeddingStatusPending
+		} else {
+			likeEmbeddings, err := utils.GenerateLikeEmbeddings(ctx, req.Like)
+			if err != nil {
+				span.SetStatus(codes.Error, "generate embedding failed")
+				return nil, err
+			}
+			embedding, err := utils.ProfileEmbedding(likeEmbeddings)
+			if err != nil {
+				span.SetStatus(codes.Error, "generate embedding failed")
+				return nil, err
+			}
+			embeddingModel := utils.EmbeddingModel()
+			updates["like_embedding"] = &embedding
+			updates["embedding_model"] = embeddingModel
+			updates["embedding_dim"] = len(embedding.Slice())
+			updates["embedding_status"] = model.EmbeddingStatusReady
+			userLikes = model.NewUserLikes(userID, req.Like, likeEmbeddings, embeddingModel)
+		}
+	}
+	if len(updates) > 0 {
+		// 修改用户信息，同时替换每个喜好的向量或创建异步生成任务
+		err = dao.DB.Transaction(func(tx *gorm.DB) error {
+			if err := model.UpdateUser(tx, userID, updates); err != nil {
+				return err
+			}
+			if !updateLike {
+				return nil
+			}
+			if err := model.ReplaceUserLikes(tx, userID, userLikes); err != nil {
+				return err
+			}
+			if async {
+				return model.CreateEmbeddingJob(tx, userID)
+			}
+			return nil
+		})
+		// 并发修改为同一用户名时以唯一约束为准
+		if model.IsUniqueViolation(err) {
+			span.SetStatus(codes.Error, "username already exists")
+			return nil, errUsernameTaken
+		}
+		if err != nil {
+			span.SetStatus(codes.Error, "update user failed")
+			return nil, err
+		}
+	}
+	// 用户名变更后同步注册幂等键，旧用户名可以被重新注册
+	if newUsername, ok := updates["username"].(string); ok {
+		if err := dao.RedisClient.Del(ctx, registerRedisKey(oldUsername)).Err(); err != nil {
+			span.SetStatus(codes.Error, "delete register info from redis failed")
+			return nil, err
+		}
+		if err := dao.RedisClient.Set(ctx, registerRedisKey(newUsername), userID, registerRedisTTL).Err(); err != nil {
+			span.SetStatus(codes.Error, "store register info to redis failed")
+			return nil, err
+		}
+	}
+	span.AddEvent("update profile success")
+	// 返回修改后的用户信息
+	user, err = model.GetUserByUserID(dao.DB, userID)
+	if err != nil {
+		span.SetStatus(codes.Error, "get user failed")
+		return nil, err
+	}
+	return toUserInfoResp(user), nil
+}
+
+// toUserInfoResp 将用户信息转换为响应结构
+func toUserInfoResp(user *model.User) *pb.UserInfoResp {
+	resp := &pb.UserInfoResp{
+		UserId:          user.UserID,
+		Like:            user.GetLikeList(),
+		CreateAt:        user.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdateAt:        user.UpdatedAt.Format("2006-01-02 15:04:05"),
+		Username:        user.Username,
+		EmbeddingStatus: user.EmbeddingStatus,
+	}
+	if user.LikeEmbedding != nil {
+		resp.LikeEmbedding = user.LikeEmbedding.Slice()
+	}
+	return resp
+}
+
+// FindSimilarUsers 根据当前用户的喜好向量查找兴趣相近的用户
+func (u *UserService) FindSimilarUsers(ctx context.Context, req *pb.FindSimilarUsersReq) (*pb.FindSimilarUsersResp, error) {
+	tr := otel.Tracer("user-service")
+	_, span := tr.Start(ctx, "FindSimilarUsers")
+	defer span.End()
+	// 从上下文中获取用户 ID
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "missing user ID in context")
+		return nil, errors.New("missing user ID in context")
+	}
+	span.SetAttributes(attribute.String("user_id", userID))
+	// 参数校验
+	if req.Limit < 0 || req.MaxDistance < 0 {
+		span.SetStatus(codes.Error, "invalid request")
+		return nil, errors.New("invalid request")
+	}
+	limit := clampLimit(req.Limit, defaultSimilarLimit, maxSimilarLimit)
+	// 查询当前用户的喜好向量
+	user, err := model.GetUserByUserID(dao.DB, userID)
+	if err != nil {
+		span.SetStatus(codes.Error, "get user failed")
+		return nil, err
+	}
+	if user.LikeEmbedding == nil || user.EmbeddingStatus != model.EmbeddingStatusReady {
+		span.SetStatus(codes.Error, "like embedding not ready")
+		return nil, errors.New("like embedding not ready")
+	}
+	// 按余弦距离查找最相近的用户
+	similarUsers, err := model.FindSimilarUsers(dao.DB, *user.LikeEmbedding, user.EmbeddingModel, userID, limit, float64(req.MaxDistance))
+	if err != nil {
+		span.SetStatus(codes.Error, "find similar users failed")
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("result_count", len(similarUsers)))
+	return &pb.FindSimilarUsersResp{Users: toSimilarUserList(similarUsers)}, nil
+}
+
+// SearchUsersByInterest 将任意兴趣描述转换为词嵌入向量后按相似度分页检索用户
+func (u *UserService) SearchUsersByInterest(ctx context.Context, req *pb.SearchUsersByInterestReq) (*pb.SearchUsersByInterestResp, error) {
+	tr := otel.Tracer("user-service")
+	_, span := tr.Start(ctx, "SearchUsersByInterest")
+	defer span.End()
+	if userID, ok := userIDFromContext(ctx); ok {
+		span.SetAttributes(attribute.String("user_id", userID))
+	}
+	// 参数校验
+	query := strings.TrimSpace(req.Query)
+	if query == "" || req.PageSize < 0 {
+		span.SetStatus(codes.Error, "invalid request")
+		return nil, errors.New("invalid request")
+	}
+	span.SetAttributes(attribute.String("query", query))
+	pageSize := clampLimit(req.PageSize, defaultPageSize, maxPageSize)
+	// 解析分页游标，游标只能用于生成它的查询
+	fingerprint := queryFingerprint(query)
+	var after *model.DistanceCursor
+	if req.Cursor != "" {
+		after = &model.DistanceCursor{}
+		if err := utils.DecodeCursor(req.Cursor, after); err != nil || after.Query != fingerprint {
+			span.SetStatus(codes.Error, "invalid cursor")
+			return nil, utils.ErrInvalidCursor
+		}
+	}
+	// 使用与注册相同的流程生成查询向量
+	embedding, err := utils.GenerateEmbeddingForLikes(ctx, []string{query})
+	if err != nil {
+		span.SetStatus(codes.Error, "generate embedding failed")
+		return nil, err
+	}
+	// 多查一条用于判断是否还有下一页
+	users, err := model.SearchUsersByEmbedding(dao.DB, embedding, utils.EmbeddingModel(), pageSize+1, after)
+	if err != nil {
+		span.SetStatus(codes.Error, "search users failed")
+		return nil, err
+	}
+	resp := &pb.SearchUsersByInterestResp{}
+	if len(users) > pageSize {
+		users = users[:pageSize]
+		last := users[len(users)-1]
+		resp.NextCursor, err = utils.EncodeCursor(model.DistanceCursor{Distance: last.Distance, ID: last.ID, Query: fingerprint})
+		if err != nil {
+			span.SetStatus(codes.Error, "encode cursor failed")
+			return nil, err
+		}
+	}
+	resp.Users = toSimilarUserList(users)
+	span.SetAttributes(attribute.Int("result_count", len(resp.Users)))
+	return resp, nil
+}
+
+// FindUsersBySharedInterest 查找与当前用户某个喜好相近的用户，并返回匹配上的两个喜好
+func (u *UserService) FindUsersBySharedInterest(ctx context.Context, req *pb.FindUsersBySharedInterestReq) (*pb.FindUsersBySharedInterestResp, error) {
+	tr := otel.Tracer("user-service")
+	_, span := tr.Start(ctx, "FindUsersBySharedInterest")
+	defer span.End()
+	// 从上下文中获取用户 ID
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "missing user ID in context")
+		return nil, errors.New("missing user ID in context")
+	}
+	span.SetAttributes(attribute.String("user_id", userID))
+	// 参数校验
+	if req.Limit < 0 || req.MaxDistance < 0 {
+		span.SetStatus(codes.Error, "invalid request")
+		return nil, errors.New("invalid request")
+	}
+	limit := clampLimit(req.Limit, defaultSimilarLimit, maxSimilarLimit)
+	// 按单个喜好匹配
+	matches, err := model.FindUsersBySharedInterest(dao.DB, userID, limit, float64(req.MaxDistance))
+	if err != nil {
+		span.SetStatus(codes.Error, "find users by shared interest failed")
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("result_count", len(matches)))
+	return &pb.FindUsersBySharedInterestResp{Matches: toInterestMatchList(matches)}, nil
+}
+
+// FindUsersByLike 查找拥有与给定喜好相近喜好的用户，并返回对方匹配上的喜好
+func (u *UserService) FindUsersByLike(ctx context.Context, req *pb.FindUsersByLikeReq) (*pb.FindUsersByLikeResp, error) {
+	tr := otel.Tracer("user-service")
+	_, span := tr.Start(ctx, "FindUsersByLike")
+	defer span.End()
+	// 从上下文中获取用户 ID
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "missing user ID in context")
+		return nil, errors.New("missing user ID in context")
+	}
+	span.SetAttributes(attribute.String("user_id", userID))
+	// 参数校验
+	like := strings.TrimSpace(req.Like)
+	if like == "" || req.Limit < 0 || req.MaxDistance < 0 {
+		span.SetStatus(codes.Error, "invalid request")
+		return nil, errors.New("invalid request")
+	}
+	limit := clampLimit(req.Limit, defaultSimilarLimit, maxSimilarLimit)
+	// 生成喜好向量
+	embeddings, err := utils.GenerateLikeEmbeddings(ctx, []string{like})
+	if err != nil {
+		span.SetStatus(codes.Error, "generate embedding failed")
+		return nil, err
+	}
+	matches, err := model.FindUsersByLikeEmbedding(dao.DB, pgvector.NewVector(embeddings[0]), utils.EmbeddingModel(), userID, limit, float64(req.MaxDistance))
+	if err != nil {
+		span.SetStatus(codes.Error, "find users by like failed")
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("result_count", len(matches)))
+	return &pb.FindUsersByLikeResp{Matches: toInterestMatchList(matches)}, nil
+}
+
+// toInterestMatchList 将按兴趣匹配的结果转换为响应结构
+func toInterestMatchList(matches []model.InterestMatch) []*pb.InterestMatch {
+	list := make([]*pb.InterestMatch, 0, len(matches))
+	for _, match := range matches {
+		list = append(list, &pb.InterestMatch{
+			UserId:      match.UserID,
+			Username:    match.Username,
+			MyLike:      match.MyLike,
+			MatchedLike: match.MatchedLike,
+			Distance:    float32(match.Distance),
+		})
+	}
+	return list
+}
+
+// clampLimit 处理分页数量，0 使用默认值，超过上限时取上限
+func clampLimit(limit int32, defaultLimit, maxLimit int) int {
+	if limit <= 0 {
+		return defaultLimit
+	}
+	if int(limit) > maxLimit {
+		return maxLimit
+	}
+	return int(limit)
+}
+
+// toSimilarUserList 将相似用户查询结果转换为响应结构
+func toSimilarUserList(users []model.SimilarUser) []*pb.SimilarUser {
+	list := make([]*pb.SimilarUser, 0, len(users))
+	for _, user := range users {
+		list = append(list, &pb.SimilarUser{
+			UserId:   user.UserID,
+			Username: user.Username,
+			Like:     user.GetLikeList(),
+			Distance: float32(user.Distance),
+		})
+	}
+	return list
+}
+
+// queryFingerprint 计算查询文本的指纹，写入游标用于校验
+func queryFingerprint(query string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(query)))
+	return hex.EncodeToString(sum[:8])
 }
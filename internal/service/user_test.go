@@ -0,0 +1,33 @@
+package service
+
+import (
+	"testing"
+
+	pb "github.com/HCH1212/taxin/api/pb/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// 按用户名和按 user_id 登录都只查询一次数据库，避免通过耗时区分标识类型
+func TestFindLoginUserSingleQuery(t *testing.T) {
+	db := setupDryRunDB(t)
+	var statements []string
+	err := db.Callback().Query().After("gorm:query").Register("test:record", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	})
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		req    *pb.LoginReq
+		column string
+	}{
+		{&pb.LoginReq{Username: "alice"}, "username"},
+		{&pb.LoginReq{UserId: "user-1"}, "user_id"},
+	} {
+		statements = nil
+		findLoginUser(tt.req)
+		require.Len(t, statements, 1)
+		assert.Contains(t, statements[0], tt.column+" = ")
+	}
+}
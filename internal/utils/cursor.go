@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidCursor 分页游标格式错误
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EncodeCursor 将分页位置编码为对客户端不透明的游标字符串
+func EncodeCursor(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor 解析 EncodeCursor 生成的游标
+func DecodeCursor(cursor string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	type position struct {
+		Distance float64 `json:"d"`
+		ID       uint    `json:"i"`
+	}
+	cursor, err := EncodeCursor(position{Distance: 0.125, ID: 42})
+	assert.NoError(t, err)
+
+	var got position
+	assert.NoError(t, DecodeCursor(cursor, &got))
+	assert.Equal(t, position{Distance: 0.125, ID: 42}, got)
+
+	assert.ErrorIs(t, DecodeCursor("not a cursor!", &got), ErrInvalidCursor)
+}
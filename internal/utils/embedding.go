@@ -1,83 +1,247 @@
 package utils
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
-	"os"
+	"log"
 
 	"github.com/HCH1212/taxin/config"
+	"github.com/HCH1212/taxin/internal/dao"
 	"github.com/pgvector/pgvector-go"
+	"golang.org/x/sync/errgroup"
 )
 
-// 使用本地ollama all-minilm-l6-v2模型，仅支持英文，768维向量
+// Embedder 词嵌入向量生成器，不同的向量服务提供方各自实现
+type Embedder interface {
+	// Embed 为一段文本生成词嵌入向量
+	Embed(ctx context.Context, text string) ([]float32, error)
+	// Model 返回生成向量所用的模型名称
+	Model() string
+}
 
-// GenerateEmbeddingForLikes 根据文本生成词嵌入向量
-func GenerateEmbeddingForLikes(ctx context.Context, likes []string) (pgvector.Vector, error) {
-	var allEmbeddings []float32
-	var baseURL string
-	var model string
-
-	if os.Getenv("GO_ENV") == "" {
-		baseURL = "http://127.0.0.1:11434/api/embeddings"
-		model = "chroma/all-minilm-l6-v2-f32:latest"
-	} else {
-		baseURL = config.GetConf().Ollama.Address
-		model = config.GetConf().Ollama.Model
-	}
-
-	for _, like := range likes {
-		// 构建请求体
-		requestBody := map[string]string{
-			"model":  model,
-			"prompt": like,
-		}
-		jsonBody, err := json.Marshal(requestBody)
-		if err != nil {
-			return pgvector.NewVector(nil), err
+// BatchEmbedder 支持一次请求为多段文本生成向量的向量生成器
+type BatchEmbedder interface {
+	Embedder
+	// EmbedBatch 为多段文本生成向量，返回顺序与 texts 一致
+	EmbedBatch(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// 多个喜好向量的聚合策略
+const (
+	AggregationMean         = "mean"          // 各维度取平均值
+	AggregationWeightedMean = "weighted_mean" // 按喜好顺序加权平均，越靠前的喜好权重越大
+	AggregationMax          = "max"           // 各维度取最大值
+)
+
+// 未配置时的默认值
+const (
+	defaultEmbeddingDimension   = 768 // 与 users.like_embedding 列一致
+	defaultEmbeddingConcurrency = 4
+	defaultEmbeddingBatchSize   = 16
+)
+
+// ErrDimensionMismatch 模型输出的向量维度与配置的列维度不一致
+var ErrDimensionMismatch = errors.New("embedding dimension mismatch")
+
+var (
+	// DefaultEmbedder 全局使用的向量生成器，由 InitEmbedder 根据配置创建
+	DefaultEmbedder Embedder
+	// EmbeddingDimension 用户画像向量的维度
+	EmbeddingDimension = defaultEmbeddingDimension
+	// EmbeddingAggregation 多个喜好向量的聚合策略
+	EmbeddingAggregation = AggregationMean
+	// EmbeddingConcurrency 同时向向量服务发出的最大请求数
+	EmbeddingConcurrency = defaultEmbeddingConcurrency
+	// EmbeddingBatchSize 支持批量接口时每个请求包含的文本数
+	EmbeddingBatchSize = defaultEmbeddingBatchSize
+)
+
+// InitEmbedder 根据配置初始化全局向量生成器
+func InitEmbedder() {
+	conf := config.GetConf()
+	embedder, err := NewEmbedder(conf.Embedding, conf.Ollama)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if conf.Embedding.Dimension > 0 {
+		EmbeddingDimension = conf.Embedding.Dimension
+	}
+	if conf.Embedding.Aggregation != "" {
+		if _, err := PoolEmbeddings([][]float32{{0}}, conf.Embedding.Aggregation); err != nil {
+			log.Fatal(err)
 		}
+		EmbeddingAggregation = conf.Embedding.Aggregation
+	}
+	if conf.Embedding.Concurrency > 0 {
+		EmbeddingConcurrency = conf.Embedding.Concurrency
+	}
+	if conf.Embedding.BatchSize > 0 {
+		EmbeddingBatchSize = conf.Embedding.BatchSize
+	}
+	// 缓存依赖 Redis，需在 dao.InitRedis 之后调用
+	if conf.Embedding.Cache.Enabled {
+		embedder = NewCachedEmbedder(embedder, dao.RedisClient, EmbeddingDimension, conf.Embedding.Cache.TTL)
+	}
+	DefaultEmbedder = embedder
+}
 
-		// 创建 HTTP 请求
-		req, err := http.NewRequestWithContext(ctx, "POST", baseURL, bytes.NewBuffer(jsonBody))
-		if err != nil {
-			return pgvector.NewVector(nil), err
+// EmbeddingModel 返回当前生成向量所用的模型名称，向量只能与同一模型生成的向量比较
+func EmbeddingModel() string {
+	if DefaultEmbedder == nil {
+		return ""
+	}
+	return DefaultEmbedder.Model()
+}
+
+// NewEmbedder 根据 provider 创建对应的向量生成器
+func NewEmbedder(conf config.Embedding, ollama config.Ollama) (Embedder, error) {
+	client := NewEmbeddingHTTPClient(conf.Timeout, conf.MaxRetries, conf.RetryBackoff, conf.Concurrency)
+	switch conf.Provider {
+	case "", "ollama":
+		return NewOllamaEmbedder(ollama.Address, ollama.Model, client), nil
+	case "openai":
+		return NewOpenAIEmbedder(conf.OpenAI.Address, conf.OpenAI.Model, conf.OpenAI.APIKey, conf.Dimension, client), nil
+	case "hash":
+		return NewHashEmbedder(conf.Dimension), nil
+	default:
+		return nil, fmt.Errorf("unknown embedding provider: %s", conf.Provider)
+	}
+}
+
+// GenerateEmbeddingForLikes 为每个喜好生成词嵌入向量，并按配置的策略聚合为固定维度的用户画像向量
+func GenerateEmbeddingForLikes(ctx context.Context, likes []string) (pgvector.Vector, error) {
+	embeddings, err := GenerateLikeEmbeddings(ctx, likes)
+	if err != nil {
+		return pgvector.NewVector(nil), err
+	}
+	return ProfileEmbedding(embeddings)
+}
+
+// GenerateLikeEmbeddings 为每个喜好单独生成词嵌入向量，返回顺序与 likes 一致
+func GenerateLikeEmbeddings(ctx context.Context, likes []string) ([][]float32, error) {
+	if DefaultEmbedder == nil {
+		return nil, errors.New("embedder not initialized")
+	}
+	if len(likes) == 0 {
+		return nil, errors.New("no likes to embed")
+	}
+
+	// 先归一化再生成向量，开启缓存与否、单条或批量请求时模型收到的文本都相同
+	normalized := make([]string, len(likes))
+	for i, like := range likes {
+		normalized[i] = NormalizeLikeText(like)
+	}
+	likes = normalized
+
+	// 支持批量接口时按 EmbeddingBatchSize 分批，否则每个喜好单独请求，各请求并发执行
+	batchSize := 1
+	if _, ok := DefaultEmbedder.(BatchEmbedder); ok && EmbeddingBatchSize > 1 {
+		batchSize = EmbeddingBatchSize
+	}
+	embeddings := make([][]float32, len(likes))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(EmbeddingConcurrency)
+	for start := 0; start < len(likes); start += batchSize {
+		end := start + batchSize
+		if end > len(likes) {
+			end = len(likes)
 		}
-		req.Header.Set("Content-Type", "application/json")
+		g.Go(func() error {
+			batch, err := embedBatch(gctx, DefaultEmbedder, likes[start:end])
+			if err != nil {
+				return err
+			}
+			copy(embeddings[start:end], batch)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
 
-		// 发送请求
-		client := &http.Client{}
-		resp, err := client.Do(req)
-		if err != nil {
-			return pgvector.NewVector(nil), err
+	// 模型输出维度必须与数据库列一致，否则写入时才会失败
+	for _, embedding := range embeddings {
+		if len(embedding) != EmbeddingDimension {
+			return nil, fmt.Errorf("%w: model %s returned %d dimensions, expected %d",
+				ErrDimensionMismatch, DefaultEmbedder.Model(), len(embedding), EmbeddingDimension)
 		}
-		defer resp.Body.Close()
+	}
+	return embeddings, nil
+}
 
-		// 读取响应
-		body, err := io.ReadAll(resp.Body)
+// embedBatch 为多段文本生成向量，向量生成器不支持批量接口时逐条请求
+func embedBatch(ctx context.Context, embedder Embedder, texts []string) ([][]float32, error) {
+	if batchEmbedder, ok := embedder.(BatchEmbedder); ok && len(texts) > 1 {
+		return batchEmbedder.EmbedBatch(ctx, texts)
+	}
+	embeddings := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		embedding, err := embedder.Embed(ctx, text)
 		if err != nil {
-			return pgvector.NewVector(nil), err
+			return nil, err
 		}
+		embeddings = append(embeddings, embedding)
+	}
+	return embeddings, nil
+}
 
-		// 检查响应状态码
-		if resp.StatusCode != http.StatusOK {
-			return pgvector.NewVector(nil), fmt.Errorf("request failed with status code: %d, body: %s", resp.StatusCode, string(body))
+// ProfileEmbedding 按配置的聚合策略将各喜好向量聚合为用户画像向量
+func ProfileEmbedding(embeddings [][]float32) (pgvector.Vector, error) {
+	pooled, err := PoolEmbeddings(embeddings, EmbeddingAggregation)
+	if err != nil {
+		return pgvector.NewVector(nil), err
+	}
+	return pgvector.NewVector(pooled), nil
+}
+
+// PoolEmbeddings 将多个等长向量聚合为一个同维度的向量
+func PoolEmbeddings(embeddings [][]float32, strategy string) ([]float32, error) {
+	if len(embeddings) == 0 {
+		return nil, errors.New("no embeddings to pool")
+	}
+	dimension := len(embeddings[0])
+	for _, embedding := range embeddings {
+		if len(embedding) != dimension {
+			return nil, fmt.Errorf("%w: got %d and %d", ErrDimensionMismatch, dimension, len(embedding))
 		}
+	}
 
-		// 解析响应
-		var result struct {
-			Embedding []float32 `json:"embedding"`
+	pooled := make([]float32, dimension)
+	switch strategy {
+	case AggregationMean:
+		for _, embedding := range embeddings {
+			for i, v := range embedding {
+				pooled[i] += v
+			}
 		}
-		err = json.Unmarshal(body, &result)
-		if err != nil {
-			return pgvector.NewVector(nil), err
+		for i := range pooled {
+			pooled[i] /= float32(len(embeddings))
 		}
-
-		// 将当前喜好的嵌入向量追加到总向量中
-		allEmbeddings = append(allEmbeddings, result.Embedding...)
+	case AggregationWeightedMean:
+		// 第 n 个喜好的权重为 1/n
+		var totalWeight float32
+		for n, embedding := range embeddings {
+			weight := 1 / float32(n+1)
+			totalWeight += weight
+			for i, v := range embedding {
+				pooled[i] += v * weight
+			}
+		}
+		for i := range pooled {
+			pooled[i] /= totalWeight
+		}
+	case AggregationMax:
+		copy(pooled, embeddings[0])
+		for _, embedding := range embeddings[1:] {
+			for i, v := range embedding {
+				if v > pooled[i] {
+					pooled[i] = v
+				}
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown embedding aggregation: %s", strategy)
 	}
-
-	return pgvector.NewVector(allEmbeddings), nil
+	return pooled, nil
 }
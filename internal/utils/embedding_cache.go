@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"expvar"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// 缓存命中统计，通过 pprof 端口的 /debug/vars 查看
+var (
+	embeddingCacheHits   = expvar.NewInt("embedding_cache_hits")
+	embeddingCacheMisses = expvar.NewInt("embedding_cache_misses")
+)
+
+// 未配置时的默认缓存时间
+const defaultEmbeddingCacheTTL = 7 * 24 * time.Hour
+
+// CachedEmbedder 在向量生成器前增加 Redis 缓存，相同模型和维度下归一化后相同的文本只生成一次向量
+type CachedEmbedder struct {
+	next   Embedder
+	client *redis.Client
+	dim    int
+	ttl    time.Duration
+}
+
+// cachedBatchEmbedder 下层生成器支持批量接口时使用，批量读取缓存后只为未命中的文本请求一次
+type cachedBatchEmbedder struct {
+	*CachedEmbedder
+}
+
+// NewCachedEmbedder 创建带缓存的向量生成器，dim 为期望的向量维度，ttl <= 0 时使用默认缓存时间
+// 只有下层生成器支持批量接口时返回的生成器才支持批量接口，否则由调用方按并发数逐条请求
+func NewCachedEmbedder(next Embedder, client *redis.Client, dim int, ttl time.Duration) Embedder {
+	if ttl <= 0 {
+		ttl = defaultEmbeddingCacheTTL
+	}
+	e := &CachedEmbedder{
+		next:   next,
+		client: client,
+		dim:    dim,
+		ttl:    ttl,
+	}
+	if _, ok := next.(BatchEmbedder); ok {
+		return cachedBatchEmbedder{e}
+	}
+	return e
+}
+
+func (e *CachedEmbedder) Model() string {
+	return e.next.Model()
+}
+
+// Embed 优先读取缓存，缓存不可用时直接调用下层生成器，不影响主流程
+// 归一化后的文本只用于缓存键，未命中时仍使用原文生成向量，与不开启缓存时的结果一致
+func (e *CachedEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	key := EmbeddingCacheKey(e.next.Model(), e.dim, NormalizeLikeText(text))
+
+	data, err := e.client.Get(ctx, key).Bytes()
+	if err == nil {
+		if embedding, err := decodeEmbedding(data); err == nil {
+			embeddingCacheHits.Add(1)
+			return embedding, nil
+		}
+	} else if err != redis.Nil {
+		log.Printf("embedding cache get failed: %v", err)
+	}
+	embeddingCacheMisses.Add(1)
+
+	embedding, err := e.next.Embed(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.client.Set(ctx, key, encodeEmbedding(embedding), e.ttl).Err(); err != nil {
+		log.Printf("embedding cache set failed: %v", err)
+	}
+	return embedding, nil
+}
+
+// EmbedBatch 批量读取缓存，只为未命中的文本生成向量
+func (e cachedBatchEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	keys := make([]string, len(texts))
+	for i, text := range texts {
+		keys[i] = EmbeddingCacheKey(e.next.Model(), e.dim, NormalizeLikeText(text))
+	}
+
+	embeddings := make([][]float32, len(texts))
+	values, err := e.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		log.Printf("embedding cache mget failed: %v", err)
+	}
+	var missIndexes []int
+	var missTexts []string
+	for i := range texts {
+		if i < len(values) {
+			if value, ok := values[i].(string); ok {
+				if embedding, err := decodeEmbedding([]byte(value)); err == nil {
+					embeddings[i] = embedding
+					continue
+				}
+			}
+		}
+		missIndexes = append(missIndexes, i)
+		missTexts = append(missTexts, texts[i])
+	}
+	embeddingCacheHits.Add(int64(len(texts) - len(missIndexes)))
+	embeddingCacheMisses.Add(int64(len(missIndexes)))
+	if len(missTexts) == 0 {
+		return embeddings, nil
+	}
+
+	generated, err := embedBatch(ctx, e.next, missTexts)
+	if err != nil {
+		return nil, err
+	}
+	pipe := e.client.Pipeline()
+	for j, i := range missIndexes {
+		embeddings[i] = generated[j]
+		pipe.Set(ctx, keys[i], encodeEmbedding(generated[j]), e.ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("embedding cache set failed: %v", err)
+	}
+	return embeddings, nil
+}
+
+// EmbeddingCacheStats 返回缓存命中和未命中次数
+func EmbeddingCacheStats() (hits, misses int64) {
+	return embeddingCacheHits.Value(), embeddingCacheMisses.Value()
+}
+
+// NormalizeLikeText 归一化喜好文本：去除首尾空白、合并连续空白并转为小写
+func NormalizeLikeText(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+// EmbeddingCacheKey 生成缓存键，同一模型修改维度后不会读到旧维度的向量，文本取哈希避免键过长
+func EmbeddingCacheKey(model string, dim int, text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return "embedding:cache:" + model + ":" + strconv.Itoa(dim) + ":" + hex.EncodeToString(sum[:])
+}
+
+// encodeEmbedding 将向量编码为小端序的 float32 字节序列
+func encodeEmbedding(embedding []float32) []byte {
+	data := make([]byte, 4*len(embedding))
+	for i, v := range embedding {
+		binary.LittleEndian.PutUint32(data[4*i:], math.Float32bits(v))
+	}
+	return data
+}
+
+// decodeEmbedding 解析 encodeEmbedding 生成的字节序列
+func decodeEmbedding(data []byte) ([]float32, error) {
+	if len(data) == 0 || len(data)%4 != 0 {
+		return nil, errors.New("invalid cached embedding")
+	}
+	embedding := make([]float32, len(data)/4)
+	for i := range embedding {
+		embedding[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+	}
+	return embedding, nil
+}
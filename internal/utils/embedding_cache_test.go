@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbeddingCacheKey(t *testing.T) {
+	assert.Equal(t, "mountain biking", NormalizeLikeText("  Mountain \t Biking "))
+	assert.Equal(t,
+		EmbeddingCacheKey("model", 768, NormalizeLikeText("Reading")),
+		EmbeddingCacheKey("model", 768, NormalizeLikeText(" reading")))
+	assert.NotEqual(t,
+		EmbeddingCacheKey("model-a", 768, "reading"),
+		EmbeddingCacheKey("model-b", 768, "reading"))
+	// 同一模型修改维度后不能命中旧维度的缓存
+	assert.NotEqual(t,
+		EmbeddingCacheKey("model", 768, "reading"),
+		EmbeddingCacheKey("model", 1024, "reading"))
+}
+
+func TestEncodeEmbedding(t *testing.T) {
+	embedding := []float32{0.25, -1.5, 3}
+	decoded, err := decodeEmbedding(encodeEmbedding(embedding))
+	assert.NoError(t, err)
+	assert.Equal(t, embedding, decoded)
+
+	_, err = decodeEmbedding([]byte{1, 2, 3})
+	assert.Error(t, err)
+}
+
+// singleEmbedder 只暴露 Embedder 接口，隐藏下层的批量接口
+type singleEmbedder struct {
+	Embedder
+}
+
+func TestNewCachedEmbedderBatch(t *testing.T) {
+	hash := NewHashEmbedder(16)
+	_, ok := NewCachedEmbedder(hash, nil, 16, 0).(BatchEmbedder)
+	assert.True(t, ok)
+	// 下层不支持批量接口时不能声明支持，调用方才会按并发数逐条请求
+	_, ok = NewCachedEmbedder(singleEmbedder{hash}, nil, 16, 0).(BatchEmbedder)
+	assert.False(t, ok)
+}
+
+// recordingEmbedder 记录下层生成器实际收到的文本
+type recordingEmbedder struct {
+	*HashEmbedder
+	mu    sync.Mutex
+	texts []string
+}
+
+func (e *recordingEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	e.mu.Lock()
+	e.texts = append(e.texts, text)
+	e.mu.Unlock()
+	return e.HashEmbedder.Embed(ctx, text)
+}
+
+func (e *recordingEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	e.mu.Lock()
+	e.texts = append(e.texts, texts...)
+	e.mu.Unlock()
+	return e.HashEmbedder.EmbedBatch(ctx, texts)
+}
+
+func TestCachedEmbedderEmbedsOriginalText(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	next := &recordingEmbedder{HashEmbedder: NewHashEmbedder(16)}
+	embedder := NewCachedEmbedder(next, client, 16, 0)
+	ctx := context.Background()
+
+	// 未命中时下层收到原文，结果与不开启缓存时一致
+	embedding, err := embedder.Embed(ctx, "  Reading ")
+	assert.NoError(t, err)
+	uncached, err := next.HashEmbedder.Embed(ctx, "  Reading ")
+	assert.NoError(t, err)
+	assert.Equal(t, uncached, embedding)
+	assert.Equal(t, []string{"  Reading "}, next.texts)
+
+	// 归一化后相同的文本命中缓存
+	_, err = embedder.Embed(ctx, "reading")
+	assert.NoError(t, err)
+	assert.Len(t, next.texts, 1)
+
+	_, err = embedder.(BatchEmbedder).EmbedBatch(ctx, []string{"READING", " Hiking"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"  Reading ", " Hiking"}, next.texts)
+}
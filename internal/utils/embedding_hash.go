@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// HashEmbedder 基于特征哈希的本地向量生成器，结果确定且不依赖外部服务，用于测试和离线开发
+// 包含相同词语的文本会得到相近的向量，但不具备真正的语义理解能力
+type HashEmbedder struct {
+	dimension int
+}
+
+// 未配置维度时的默认向量维度
+const defaultHashDimension = 768
+
+// NewHashEmbedder 创建指定维度的哈希向量生成器
+func NewHashEmbedder(dimension int) *HashEmbedder {
+	if dimension <= 0 {
+		dimension = defaultHashDimension
+	}
+	return &HashEmbedder{dimension: dimension}
+}
+
+func (e *HashEmbedder) Model() string {
+	return "hash"
+}
+
+func (e *HashEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	embedding := make([]float32, e.dimension)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for _, word := range words {
+		h := fnv.New64a()
+		h.Write([]byte(word))
+		sum := h.Sum64()
+		// 低位决定落在哪一维，最高位决定符号，减少哈希冲突带来的偏差
+		index := sum % uint64(e.dimension)
+		if sum>>63 == 1 {
+			embedding[index] -= 1
+		} else {
+			embedding[index] += 1
+		}
+	}
+
+	// 归一化为单位向量
+	var norm float64
+	for _, v := range embedding {
+		norm += float64(v) * float64(v)
+	}
+	if norm > 0 {
+		norm = math.Sqrt(norm)
+		for i := range embedding {
+			embedding[i] = float32(float64(embedding[i]) / norm)
+		}
+	}
+	return embedding, nil
+}
+
+// EmbedBatch 为多段文本生成向量
+func (e *HashEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		embedding, err := e.Embed(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		embeddings = append(embeddings, embedding)
+	}
+	return embeddings, nil
+}
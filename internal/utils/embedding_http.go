@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+)
+
+// 未配置时的默认 HTTP 调用参数
+const (
+	defaultEmbeddingTimeout      = 10 * time.Second
+	defaultEmbeddingMaxRetries   = 2
+	defaultEmbeddingRetryBackoff = 200 * time.Millisecond
+	maxEmbeddingRetryBackoff     = 5 * time.Second
+)
+
+// EmbeddingHTTPClient 向量服务共用的 HTTP 客户端，复用连接池，支持单次请求超时和失败重试
+type EmbeddingHTTPClient struct {
+	client     *http.Client
+	timeout    time.Duration
+	maxRetries int
+	backoff    time.Duration
+}
+
+// NewEmbeddingHTTPClient 创建 HTTP 客户端，maxConns 为同一服务的最大空闲连接数，一般与并发数一致
+// timeout、backoff 小于等于 0 时使用默认值，maxRetries 小于 0 时使用默认值，为 0 表示不重试
+func NewEmbeddingHTTPClient(timeout time.Duration, maxRetries int, backoff time.Duration, maxConns int) *EmbeddingHTTPClient {
+	if timeout <= 0 {
+		timeout = defaultEmbeddingTimeout
+	}
+	if maxRetries < 0 {
+		maxRetries = defaultEmbeddingMaxRetries
+	}
+	if backoff <= 0 {
+		backoff = defaultEmbeddingRetryBackoff
+	}
+	if maxConns <= 0 {
+		maxConns = defaultEmbeddingConcurrency
+	}
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:        maxConns * 2,
+		MaxIdleConnsPerHost: maxConns,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	return &EmbeddingHTTPClient{
+		client:     &http.Client{Transport: transport},
+		timeout:    timeout,
+		maxRetries: maxRetries,
+		backoff:    backoff,
+	}
+}
+
+// defaultEmbeddingHTTPClient 未指定客户端时使用的共享客户端
+var defaultEmbeddingHTTPClient = NewEmbeddingHTTPClient(0, defaultEmbeddingMaxRetries, 0, 0)
+
+// retryableError 可以重试的错误：网络错误、429 和 5xx
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+// PostJSON 以 JSON 格式发送 POST 请求并解析响应到 out，失败时按指数退避重试
+func (c *EmbeddingHTTPClient) PostJSON(ctx context.Context, url string, header http.Header, body, out interface{}) error {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 0; ; attempt++ {
+		err = c.post(ctx, url, header, jsonBody, out)
+		if err == nil {
+			return nil
+		}
+		if _, ok := err.(*retryableError); !ok || attempt >= c.maxRetries {
+			return err
+		}
+		// 调用方已取消或已超过调用方的截止时间，重试也不会成功
+		if ctx.Err() != nil {
+			return err
+		}
+		// 指数退避并加入随机抖动，避免大量请求同时重试
+		wait := c.backoff << attempt
+		if wait > maxEmbeddingRetryBackoff {
+			wait = maxEmbeddingRetryBackoff
+		}
+		wait += time.Duration(rand.Int63n(int64(wait)/2 + 1))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// post 发送一次请求，单次请求受 timeout 限制
+func (c *EmbeddingHTTPClient) post(ctx context.Context, url string, header http.Header, jsonBody []byte, out interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// 创建 HTTP 请求
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jsonBody))
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// 发送请求
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// 调用方取消时不再重试
+		if ctx.Err() != nil && ctx.Err() != context.DeadlineExceeded {
+			return err
+		}
+		return &retryableError{err: err}
+	}
+	defer resp.Body.Close()
+
+	// 读取响应
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &retryableError{err: err}
+	}
+
+	// 检查响应状态码
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("request failed with status code: %d, body: %s", resp.StatusCode, string(respBody))
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+			return &retryableError{err: err}
+		}
+		return err
+	}
+
+	// 解析响应
+	return json.Unmarshal(respBody, out)
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// OllamaEmbedder 调用 ollama 的 /api/embed 接口生成向量
+// 单条和批量使用同一接口，旧的 /api/embeddings 返回未归一化的向量，与批量结果的长度不一致，聚合时会产生偏差
+type OllamaEmbedder struct {
+	baseURL string
+	model   string
+	client  *EmbeddingHTTPClient
+}
+
+// NewOllamaEmbedder 创建 ollama 向量生成器，address 为服务地址，例如 http://127.0.0.1:11434
+// client 为空时使用共享的默认客户端
+func NewOllamaEmbedder(address, model string, client *EmbeddingHTTPClient) *OllamaEmbedder {
+	if client == nil {
+		client = defaultEmbeddingHTTPClient
+	}
+	// 兼容旧配置中直接写完整接口地址的情况
+	baseURL := strings.TrimSuffix(strings.TrimRight(address, "/"), "/api/embeddings")
+	return &OllamaEmbedder{
+		baseURL: baseURL,
+		model:   model,
+		client:  client,
+	}
+}
+
+func (e *OllamaEmbedder) Model() string {
+	return e.model
+}
+
+func (e *OllamaEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := e.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// EmbedBatch 一次请求为多段文本生成向量
+func (e *OllamaEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	requestBody := map[string]interface{}{
+		"model": e.model,
+		"input": texts,
+	}
+	var result struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	if err := e.client.PostJSON(ctx, e.baseURL+"/api/embed", nil, requestBody, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(result.Embeddings))
+	}
+	return result.Embeddings, nil
+}
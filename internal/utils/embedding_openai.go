@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// OpenAIEmbedder 调用兼容 OpenAI 的 /v1/embeddings 接口生成向量
+type OpenAIEmbedder struct {
+	url        string
+	model      string
+	apiKey     string
+	dimensions int
+	client     *EmbeddingHTTPClient
+}
+
+// NewOpenAIEmbedder 创建 OpenAI 兼容的向量生成器，apiKey 为空时读取环境变量 OPENAI_API_KEY
+// dimensions 大于 0 时要求模型输出指定维度（text-embedding-3 系列支持），client 为空时使用共享的默认客户端
+func NewOpenAIEmbedder(url, model, apiKey string, dimensions int, client *EmbeddingHTTPClient) *OpenAIEmbedder {
+	if apiKey == "" {
+		apiKey = os.Getenv("OPENAI_API_KEY")
+	}
+	if client == nil {
+		client = defaultEmbeddingHTTPClient
+	}
+	return &OpenAIEmbedder{
+		url:        url,
+		model:      model,
+		apiKey:     apiKey,
+		dimensions: dimensions,
+		client:     client,
+	}
+}
+
+func (e *OpenAIEmbedder) Model() string {
+	return e.model
+}
+
+func (e *OpenAIEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := e.embed(ctx, text, 1)
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// EmbedBatch 一次请求为多段文本生成向量
+func (e *OpenAIEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	return e.embed(ctx, texts, len(texts))
+}
+
+// embed 发送请求，input 可以是单个字符串或字符串数组，返回结果按输入顺序排列
+func (e *OpenAIEmbedder) embed(ctx context.Context, input interface{}, count int) ([][]float32, error) {
+	requestBody := map[string]interface{}{
+		"model": e.model,
+		"input": input,
+	}
+	if e.dimensions > 0 {
+		requestBody["dimensions"] = e.dimensions
+	}
+	header := http.Header{}
+	if e.apiKey != "" {
+		header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	var result struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := e.client.PostJSON(ctx, e.url, header, requestBody, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Data) != count {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", count, len(result.Data))
+	}
+	embeddings := make([][]float32, count)
+	for _, item := range result.Data {
+		if item.Index < 0 || item.Index >= count {
+			return nil, fmt.Errorf("unexpected embedding index: %d", item.Index)
+		}
+		embeddings[item.Index] = item.Embedding
+	}
+	return embeddings, nil
+}
@@ -2,14 +2,195 @@ package utils
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/HCH1212/taxin/config"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestGenerateEmbeddingForLikes(t *testing.T) {
+	DefaultEmbedder = NewHashEmbedder(768)
+	defer func() { DefaultEmbedder = nil }()
+
 	likes := []string{"apple", "banana", "orange"}
 	embedding, err := GenerateEmbeddingForLikes(context.Background(), likes)
 	if err != nil {
 		t.Error(err)
 	}
-	t.Log(embedding)
+	// 无论有几个喜好，聚合后的维度都与列维度一致
+	assert.Len(t, embedding.Slice(), EmbeddingDimension)
+
+	// 分批并发生成时结果顺序与输入一致
+	EmbeddingBatchSize, EmbeddingConcurrency = 2, 2
+	defer func() {
+		EmbeddingBatchSize, EmbeddingConcurrency = defaultEmbeddingBatchSize, defaultEmbeddingConcurrency
+	}()
+	embeddings, err := GenerateLikeEmbeddings(context.Background(), likes)
+	assert.NoError(t, err)
+	for i, like := range likes {
+		expected, _ := DefaultEmbedder.Embed(context.Background(), like)
+		assert.Equal(t, expected, embeddings[i])
+	}
+
+	// 生成前先归一化，大小写和空白不同的喜好得到相同的向量
+	embeddings, err = GenerateLikeEmbeddings(context.Background(), []string{" Apple ", "apple"})
+	assert.NoError(t, err)
+	assert.Equal(t, embeddings[0], embeddings[1])
+
+	// 模型输出维度与列维度不一致时报错
+	DefaultEmbedder = NewHashEmbedder(384)
+	_, err = GenerateEmbeddingForLikes(context.Background(), likes)
+	assert.ErrorIs(t, err, ErrDimensionMismatch)
+}
+
+func TestPoolEmbeddings(t *testing.T) {
+	embeddings := [][]float32{{1, 0, 4}, {3, 2, -2}}
+
+	mean, err := PoolEmbeddings(embeddings, AggregationMean)
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{2, 1, 1}, mean)
+
+	// 权重分别为 1 和 1/2
+	weighted, err := PoolEmbeddings(embeddings, AggregationWeightedMean)
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float32{5.0 / 3, 2.0 / 3, 2}, weighted, 1e-6)
+
+	maxPooled, err := PoolEmbeddings(embeddings, AggregationMax)
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{3, 2, 4}, maxPooled)
+
+	_, err = PoolEmbeddings([][]float32{{1, 2}, {1}}, AggregationMean)
+	assert.ErrorIs(t, err, ErrDimensionMismatch)
+
+	_, err = PoolEmbeddings(embeddings, "median")
+	assert.Error(t, err)
+}
+
+func TestHashEmbedder(t *testing.T) {
+	embedder, err := NewEmbedder(config.Embedding{Provider: "hash", Dimension: 16}, config.Ollama{})
+	assert.NoError(t, err)
+
+	first, err := embedder.Embed(context.Background(), "Mountain biking")
+	assert.NoError(t, err)
+	second, err := embedder.Embed(context.Background(), "mountain  biking!")
+	assert.NoError(t, err)
+	assert.Len(t, first, 16)
+	assert.Equal(t, first, second)
+}
+
+func TestOllamaEmbedder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 单条文本与批量使用同一接口，返回的向量都已归一化
+		assert.Equal(t, "/api/embed", r.URL.Path)
+		var body struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "test-model", body.Model)
+		assert.Equal(t, []string{"reading"}, body.Input)
+		w.Write([]byte(`{"embeddings":[[0.1,0.2,0.3]]}`))
+	}))
+	defer server.Close()
+
+	embedder := NewOllamaEmbedder(server.URL+"/api/embeddings", "test-model", nil)
+	embedding, err := embedder.Embed(context.Background(), "reading")
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0.1, 0.2, 0.3}, embedding)
+}
+
+func TestOpenAIEmbedder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer sk-test", r.Header.Get("Authorization"))
+		var body map[string]interface{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "reading", body["input"])
+		assert.NotContains(t, body, "dimensions")
+		w.Write([]byte(`{"data":[{"index":0,"embedding":[0.4,0.5]}]}`))
+	}))
+	defer server.Close()
+
+	embedder := NewOpenAIEmbedder(server.URL+"/v1/embeddings", "test-model", "sk-test", 0, NewEmbeddingHTTPClient(0, 0, 0, 0))
+	embedding, err := embedder.Embed(context.Background(), "reading")
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0.4, 0.5}, embedding)
+
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	_, err = embedder.Embed(context.Background(), "reading")
+	assert.Error(t, err)
+}
+
+func TestOllamaEmbedderBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/embed", r.URL.Path)
+		var body struct {
+			Input []string `json:"input"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, []string{"reading", "swimming"}, body.Input)
+		w.Write([]byte(`{"embeddings":[[0.1],[0.2]]}`))
+	}))
+	defer server.Close()
+
+	embedder := NewOllamaEmbedder(server.URL, "test-model", nil)
+	embeddings, err := embedder.EmbedBatch(context.Background(), []string{"reading", "swimming"})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]float32{{0.1}, {0.2}}, embeddings)
+}
+
+func TestEmbeddingHTTPClientRetry(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"data":[{"index":1,"embedding":[2]},{"index":0,"embedding":[1]}]}`))
+	}))
+	defer server.Close()
+
+	client := NewEmbeddingHTTPClient(time.Second, 2, time.Millisecond, 1)
+	embedder := NewOpenAIEmbedder(server.URL, "test-model", "sk-test", 0, client)
+	embeddings, err := embedder.EmbedBatch(context.Background(), []string{"a", "b"})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	// 按 index 还原输入顺序
+	assert.Equal(t, [][]float32{{1}, {2}}, embeddings)
+
+	// 4xx 不重试
+	calls = 0
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	_, err = embedder.Embed(context.Background(), "a")
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestEmbeddingHTTPClientParentDeadline(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// 单次请求的超时比调用方的截止时间长，超过调用方的截止时间后不再重试
+	client := NewEmbeddingHTTPClient(time.Second, 3, time.Millisecond, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	embedder := NewOllamaEmbedder(server.URL, "test-model", client)
+	_, err := embedder.Embed(ctx, "reading")
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
 }
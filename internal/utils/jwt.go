@@ -2,54 +2,159 @@ package utils
 
 import (
 	"errors"
+	"fmt"
+	"log"
 	"os"
 	"time"
 
+	"github.com/HCH1212/taxin/config"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var accessTokenKey = []byte(os.Getenv("TOKEN_SECRET"))
+// accessTokenKeys 签发和校验 token 的密钥，默认使用 TOKEN_SECRET 以 HS256 签发
+var accessTokenKeys = NewHMACKeySet([]byte(os.Getenv("TOKEN_SECRET")))
+
+var (
+	// AccessTokenTTL access_token 有效期
+	AccessTokenTTL = 15 * time.Minute
+	// RefreshTokenTTL refresh_token 有效期
+	RefreshTokenTTL = 30 * 24 * time.Hour
+	// TokenIssuer 签发方，写入 iss 并在校验时比对
+	TokenIssuer = "taxin"
+	// TokenAudience 接收方，写入 aud 并在校验时比对
+	TokenAudience = "taxin"
+	// TokenLeeway 校验 exp、nbf、iat 时允许的时钟偏差
+	TokenLeeway = 30 * time.Second
+	// TokenAlgorithms 允许的签名算法，为空时使用当前密钥的算法
+	TokenAlgorithms []string
+)
+
+var (
+	// ErrTokenMalformed token 格式错误
+	ErrTokenMalformed = errors.New("token is malformed")
+	// ErrTokenExpired token 已过期
+	ErrTokenExpired = errors.New("token is expired")
+	// ErrTokenWrongAudience token 不是签发给本服务的
+	ErrTokenWrongAudience = errors.New("token has wrong audience")
+	// ErrTokenInvalid 签名、签发方、生效时间等其他校验失败
+	ErrTokenInvalid = errors.New("invalid token")
+)
+
+// InitJWT 根据配置初始化 token 有效期、校验规则和签名密钥
+func InitJWT() {
+	conf := config.GetConf().JWT
+	if conf.AccessTTL > 0 {
+		AccessTokenTTL = conf.AccessTTL
+	}
+	if conf.RefreshTTL > 0 {
+		RefreshTokenTTL = conf.RefreshTTL
+	}
+	if conf.Issuer != "" {
+		TokenIssuer = conf.Issuer
+	}
+	if conf.Audience != "" {
+		TokenAudience = conf.Audience
+	}
+	if conf.Leeway > 0 {
+		TokenLeeway = conf.Leeway
+	}
+	if len(conf.Algorithms) > 0 {
+		TokenAlgorithms = conf.Algorithms
+	}
+	if len(conf.Keys) > 0 {
+		keys, err := LoadKeySet(conf)
+		if err != nil {
+			log.Fatal(err)
+		}
+		accessTokenKeys = keys
+	}
+}
+
+// JWKS 返回当前可用于校验 token 的公钥集合
+func JWKS() ([]byte, error) {
+	return accessTokenKeys.JWKS()
+}
 
 type Claims struct {
-	UserID string
+	UserID     string `json:"-"`    // 即 sub，解析时由 Subject 填充
+	Generation int64  `json:"gen"`  // 签发时用户的 token 代数，修改密码等操作会使代数增加，旧 token 随之失效
+	SessionID  string `json:"sid"`  // 同一次登录签发的 token 共享，与 refresh_token 家族一致
+	Role       string `json:"role"` // 签发时用户的角色
 	jwt.RegisteredClaims
 }
 
+// TokenInfo 签发 token 时写入的用户信息
+type TokenInfo struct {
+	UserID     string
+	Generation int64
+	SessionID  string
+	Role       string
+}
+
 // GetToken 生成token
-func GetToken(userID string) (string, error) {
-	// accessToken过期时间一周
-	accessTokenTime := time.Now().Add(7 * 24 * time.Hour)
+func GetToken(info TokenInfo) (string, error) {
+	// accessToken 为短期 token，过期后使用 refresh_token 换取
+	now := time.Now()
+	accessTokenTime := now.Add(AccessTokenTTL)
 
 	accessClaims := Claims{
-		UserID: userID,
+		UserID:     info.UserID,
+		Generation: info.Generation,
+		SessionID:  info.SessionID,
+		Role:       info.Role,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        GenerateUUID(), // jti，退出登录时加入黑名单
 			ExpiresAt: jwt.NewNumericDate(accessTokenTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Issuer:    "my",
-			Subject:   "token",
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    TokenIssuer,
+			Audience:  jwt.ClaimStrings{TokenAudience},
+			Subject:   info.UserID,
 		},
 	}
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
+	signing := accessTokenKeys.Signing
+	accessToken := jwt.NewWithClaims(signing.Method, accessClaims)
+	if signing.ID != "" {
+		accessToken.Header["kid"] = signing.ID
+	}
 
-	accessTokenStr, err := accessToken.SignedString(accessTokenKey)
+	accessTokenStr, err := accessToken.SignedString(signing.Sign)
 	if err != nil {
 		return "", err
 	}
 	return accessTokenStr, nil
 }
 
-// ParseAccessToken 解析token
+// ParseAccessToken 解析并校验 token，失败时返回的错误可以用 errors.Is 区分过期、格式错误和接收方错误
 func ParseAccessToken(tokenString string) (*Claims, error) {
+	algorithms := TokenAlgorithms
+	if len(algorithms) == 0 {
+		algorithms = accessTokenKeys.Algorithms()
+	}
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(algorithms),
+		jwt.WithIssuer(TokenIssuer),
+		jwt.WithAudience(TokenAudience),
+		jwt.WithLeeway(TokenLeeway),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
 	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return accessTokenKey, nil
-	})
-	if err != nil {
-		return nil, err
+	token, err := parser.ParseWithClaims(tokenString, claims, accessTokenKeys.Keyfunc)
+	switch {
+	case err == nil:
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return nil, fmt.Errorf("%w: %w", ErrTokenMalformed, err)
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return nil, ErrTokenExpired
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return nil, ErrTokenWrongAudience
+	default:
+		return nil, fmt.Errorf("%w: %w", ErrTokenInvalid, err)
 	}
-	// 有效
-	if token.Valid {
-		return claims, nil
+	if !token.Valid || claims.Subject == "" {
+		return nil, ErrTokenInvalid
 	}
-	return nil, errors.New("invalid token")
+	claims.UserID = claims.Subject
+	return claims, nil
 }
@@ -0,0 +1,190 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/HCH1212/taxin/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	// ErrUnknownKey token header 中的 kid 不在校验密钥中
+	ErrUnknownKey = errors.New("unknown signing key")
+	// ErrUnexpectedAlgorithm token 的签名算法与密钥不一致
+	ErrUnexpectedAlgorithm = errors.New("unexpected signing algorithm")
+)
+
+// JWTKey 签名或校验 token 使用的密钥
+type JWTKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	Sign   interface{} // 签名密钥，只用于校验的密钥为 nil
+	Verify interface{} // 校验密钥
+}
+
+// KeySet token 使用的密钥集合，签发使用 Signing，校验按 kid 查找
+type KeySet struct {
+	Signing *JWTKey
+	keys    map[string]*JWTKey
+}
+
+// NewHMACKeySet 使用共享密钥以 HS256 签发和校验 token
+func NewHMACKeySet(secret []byte) *KeySet {
+	key := &JWTKey{Method: jwt.SigningMethodHS256, Sign: secret, Verify: secret}
+	return &KeySet{Signing: key, keys: map[string]*JWTKey{"": key}}
+}
+
+// LoadKeySet 根据配置从文件加载非对称密钥
+func LoadKeySet(conf config.JWT) (*KeySet, error) {
+	set := &KeySet{keys: make(map[string]*JWTKey, len(conf.Keys))}
+	for _, kc := range conf.Keys {
+		if kc.ID == "" {
+			return nil, errors.New("jwt key id is required")
+		}
+		if _, ok := set.keys[kc.ID]; ok {
+			return nil, fmt.Errorf("duplicate jwt key id %q", kc.ID)
+		}
+		key, err := loadKey(kc)
+		if err != nil {
+			return nil, fmt.Errorf("load jwt key %q: %w", kc.ID, err)
+		}
+		set.keys[kc.ID] = key
+	}
+	if conf.SigningKey == "" {
+		return nil, errors.New("jwt signing_key is required when keys are configured")
+	}
+	signing, ok := set.keys[conf.SigningKey]
+	if !ok {
+		return nil, fmt.Errorf("jwt signing key %q not found", conf.SigningKey)
+	}
+	if signing.Sign == nil {
+		return nil, fmt.Errorf("jwt signing key %q has no private key", conf.SigningKey)
+	}
+	set.Signing = signing
+	return set, nil
+}
+
+func loadKey(conf config.JWTKey) (*JWTKey, error) {
+	key := &JWTKey{ID: conf.ID}
+	var privatePEM, publicPEM []byte
+	var err error
+	if conf.PrivateKeyFile != "" {
+		if privatePEM, err = os.ReadFile(conf.PrivateKeyFile); err != nil {
+			return nil, err
+		}
+	}
+	if conf.PublicKeyFile != "" {
+		if publicPEM, err = os.ReadFile(conf.PublicKeyFile); err != nil {
+			return nil, err
+		}
+	}
+	if privatePEM == nil && publicPEM == nil {
+		return nil, errors.New("private_key_file or public_key_file is required")
+	}
+
+	switch conf.Algorithm {
+	case "RS256":
+		key.Method = jwt.SigningMethodRS256
+		if privatePEM != nil {
+			private, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+			if err != nil {
+				return nil, err
+			}
+			key.Sign, key.Verify = private, &private.PublicKey
+		}
+		if publicPEM != nil {
+			if key.Verify, err = jwt.ParseRSAPublicKeyFromPEM(publicPEM); err != nil {
+				return nil, err
+			}
+		}
+	case "EdDSA":
+		key.Method = jwt.SigningMethodEdDSA
+		if privatePEM != nil {
+			private, err := jwt.ParseEdPrivateKeyFromPEM(privatePEM)
+			if err != nil {
+				return nil, err
+			}
+			key.Sign, key.Verify = private, private.(crypto.Signer).Public()
+		}
+		if publicPEM != nil {
+			if key.Verify, err = jwt.ParseEdPublicKeyFromPEM(publicPEM); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", conf.Algorithm)
+	}
+	return key, nil
+}
+
+// Keyfunc 按 token header 中的 kid 查找校验密钥，并要求签名算法与密钥一致，防止算法混淆
+func (s *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, ErrUnexpectedAlgorithm
+	}
+	return key.Verify, nil
+}
+
+// Algorithms 返回密钥集合中所有密钥的签名算法
+func (s *KeySet) Algorithms() []string {
+	seen := make(map[string]bool, len(s.keys))
+	algorithms := make([]string, 0, len(s.keys))
+	for _, key := range s.keys {
+		alg := key.Method.Alg()
+		if !seen[alg] {
+			seen[alg] = true
+			algorithms = append(algorithms, alg)
+		}
+	}
+	sort.Strings(algorithms)
+	return algorithms
+}
+
+// JWK JSON Web Key，只包含公钥
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS 返回所有非对称校验公钥，共享密钥不会对外公开
+func (s *KeySet) JWKS() ([]byte, error) {
+	keys := make([]JWK, 0, len(s.keys))
+	for _, key := range s.keys {
+		jwk := JWK{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+		switch public := key.Verify.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+		keys = append(keys, jwk)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Kid < keys[j].Kid })
+	return json.Marshal(map[string][]JWK{"keys": keys})
+}
@@ -0,0 +1,182 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HCH1212/taxin/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// writeKeyPair 生成密钥对并以 PEM 格式写入临时目录，返回私钥和公钥文件路径
+func writeKeyPair(t *testing.T, name string, private interface{}, public interface{}) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateFile := filepath.Join(dir, name+".pem")
+	publicFile := filepath.Join(dir, name+".pub.pem")
+	if err := os.WriteFile(privateFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(publicFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return privateFile, publicFile
+}
+
+// useKeySet 在测试期间替换全局密钥
+func useKeySet(t *testing.T, keys *KeySet) {
+	t.Helper()
+	old := accessTokenKeys
+	accessTokenKeys = keys
+	t.Cleanup(func() { accessTokenKeys = old })
+}
+
+func TestAsymmetricKeyRotation(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPrivateFile, rsaPublicFile := writeKeyPair(t, "old", rsaKey, &rsaKey.PublicKey)
+	edPrivateFile, _ := writeKeyPair(t, "new", edPrivate, edPublic)
+
+	// 旧密钥签发的 token
+	oldKeys, err := LoadKeySet(config.JWT{
+		SigningKey: "old",
+		Keys:       []config.JWTKey{{ID: "old", Algorithm: "RS256", PrivateKeyFile: rsaPrivateFile}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	useKeySet(t, oldKeys)
+	oldToken, err := GetToken(TokenInfo{UserID: "u1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 轮换后使用新密钥签发，旧公钥仍可校验
+	newKeys, err := LoadKeySet(config.JWT{
+		SigningKey: "new",
+		Keys: []config.JWTKey{
+			{ID: "new", Algorithm: "EdDSA", PrivateKeyFile: edPrivateFile},
+			{ID: "old", Algorithm: "RS256", PublicKeyFile: rsaPublicFile},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	useKeySet(t, newKeys)
+	newToken, err := GetToken(TokenInfo{UserID: "u2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, &Claims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Header["kid"] != "new" || parsed.Method.Alg() != "EdDSA" {
+		t.Errorf("header = %v, want kid new and alg EdDSA", parsed.Header)
+	}
+	for token, userID := range map[string]string{oldToken: "u1", newToken: "u2"} {
+		claims, err := ParseAccessToken(token)
+		if err != nil {
+			t.Fatalf("parse %s token: %v", userID, err)
+		}
+		if claims.UserID != userID {
+			t.Errorf("user id = %q, want %q", claims.UserID, userID)
+		}
+	}
+
+	// JWKS 包含两把公钥
+	body, err := JWKS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var jwks struct {
+		Keys []JWK `json:"keys"`
+	}
+	if err := json.Unmarshal(body, &jwks); err != nil {
+		t.Fatal(err)
+	}
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("jwks has %d keys, want 2", len(jwks.Keys))
+	}
+	if k := jwks.Keys[0]; k.Kid != "new" || k.Kty != "OKP" || k.Crv != "Ed25519" || k.X == "" {
+		t.Errorf("unexpected jwk %+v", k)
+	}
+	if k := jwks.Keys[1]; k.Kid != "old" || k.Kty != "RSA" || k.N == "" || k.E != "AQAB" {
+		t.Errorf("unexpected jwk %+v", k)
+	}
+}
+
+func TestKeySetRejectsUnknownKeyAndAlgorithm(t *testing.T) {
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPrivateFile, _ := writeKeyPair(t, "k1", edPrivate, edPublic)
+	keys, err := LoadKeySet(config.JWT{
+		SigningKey: "k1",
+		Keys:       []config.JWTKey{{ID: "k1", Algorithm: "EdDSA", PrivateKeyFile: edPrivateFile}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	useKeySet(t, keys)
+
+	// 不在允许列表中的算法直接拒绝，不会用公钥当作 HMAC 密钥校验
+	hmacToken := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{})
+	hmacToken.Header["kid"] = "k1"
+	signed, err := hmacToken.SignedString([]byte(edPublic))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseAccessToken(signed); !errors.Is(err, ErrTokenInvalid) {
+		t.Errorf("err = %v, want %v", err, ErrTokenInvalid)
+	}
+
+	// kid 不在校验密钥中
+	edToken := jwt.NewWithClaims(jwt.SigningMethodEdDSA, Claims{})
+	edToken.Header["kid"] = "missing"
+	signed, err = edToken.SignedString(edPrivate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseAccessToken(signed); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("err = %v, want %v", err, ErrUnknownKey)
+	}
+}
+
+func TestLoadKeySetRequiresPrivateSigningKey(t *testing.T) {
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edPublicFile := writeKeyPair(t, "k1", edPrivate, edPublic)
+	_, err = LoadKeySet(config.JWT{
+		SigningKey: "k1",
+		Keys:       []config.JWTKey{{ID: "k1", Algorithm: "EdDSA", PublicKeyFile: edPublicFile}},
+	})
+	if err == nil {
+		t.Error("expected error for signing key without private key")
+	}
+}
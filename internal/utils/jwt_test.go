@@ -1,13 +1,17 @@
 package utils
 
 import (
+	"errors"
 	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
 )
 
 func TestJWT(t *testing.T) {
 	// 生成token
 	userID := "123456"
-	token, err := GetToken(userID)
+	token, err := GetToken(TokenInfo{UserID: userID, Generation: 1, SessionID: "session", Role: "admin"})
 	if err != nil {
 		t.Error(err)
 	}
@@ -18,5 +22,93 @@ func TestJWT(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	t.Log(claims.UserID)
+	t.Log(claims.UserID, claims.Generation)
+	if claims.ID == "" {
+		t.Error("missing jti")
+	}
+	if claims.SessionID != "session" {
+		t.Errorf("session id = %q, want %q", claims.SessionID, "session")
+	}
+	if claims.Role != "admin" {
+		t.Errorf("role = %q, want %q", claims.Role, "admin")
+	}
+
+	// 每次签发的 jti 不同
+	other, err := GetToken(TokenInfo{UserID: userID, Generation: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherClaims, err := ParseAccessToken(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if otherClaims.ID == claims.ID {
+		t.Error("jti should be unique per token")
+	}
+}
+
+// signClaims 使用当前签名密钥签发自定义 claims 的 token
+func signClaims(t *testing.T, claims Claims) string {
+	t.Helper()
+	signing := accessTokenKeys.Signing
+	token, err := jwt.NewWithClaims(signing.Method, claims).SignedString(signing.Sign)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestParseAccessTokenValidation(t *testing.T) {
+	now := time.Now()
+	valid := func() Claims {
+		return Claims{RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "123456",
+			Issuer:    TokenIssuer,
+			Audience:  jwt.ClaimStrings{TokenAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		}}
+	}
+
+	claims, err := ParseAccessToken(signClaims(t, valid()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != "123456" {
+		t.Errorf("user id = %q, want sub", claims.UserID)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *Claims)
+		want   error
+	}{
+		{"expired", func(c *Claims) { c.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Hour)) }, ErrTokenExpired},
+		{"wrong audience", func(c *Claims) { c.Audience = jwt.ClaimStrings{"other"} }, ErrTokenWrongAudience},
+		{"wrong issuer", func(c *Claims) { c.Issuer = "my" }, ErrTokenInvalid},
+		{"not yet valid", func(c *Claims) { c.NotBefore = jwt.NewNumericDate(now.Add(time.Hour)) }, ErrTokenInvalid},
+		{"missing expiry", func(c *Claims) { c.ExpiresAt = nil }, ErrTokenInvalid},
+		{"missing subject", func(c *Claims) { c.Subject = "" }, ErrTokenInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid()
+			tt.modify(&c)
+			if _, err := ParseAccessToken(signClaims(t, c)); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// 在允许的时钟偏差内仍然有效
+	c := valid()
+	c.ExpiresAt = jwt.NewNumericDate(now.Add(-TokenLeeway / 2))
+	if _, err := ParseAccessToken(signClaims(t, c)); err != nil {
+		t.Errorf("token within leeway rejected: %v", err)
+	}
+
+	if _, err := ParseAccessToken("not-a-token"); !errors.Is(err, ErrTokenMalformed) {
+		t.Errorf("err = %v, want %v", err, ErrTokenMalformed)
+	}
 }
@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateRandomToken(t *testing.T) {
+	first, err := GenerateRandomToken(32)
+	assert.NoError(t, err)
+	second, err := GenerateRandomToken(32)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, second)
+	assert.Len(t, first, 43)
+
+	assert.Equal(t, HashToken(first), HashToken(first))
+	assert.NotEqual(t, HashToken(first), HashToken(second))
+}
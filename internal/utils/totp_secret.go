@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/HCH1212/taxin/config"
+)
+
+// encryptedTOTPPrefix 加密后的两步验证密钥前缀，没有前缀的是加密前保存的明文密钥
+const encryptedTOTPPrefix = "v1:"
+
+// totpSecretAEAD 加密保存两步验证密钥，未初始化时不能开启两步验证
+var totpSecretAEAD cipher.AEAD
+
+// InitTOTP 加载加密两步验证密钥使用的密钥，未在 totp.encryption_key 中配置时读取环境变量 TOTP_ENCRYPTION_KEY
+func InitTOTP() {
+	key := config.GetConf().TOTP.EncryptionKey
+	if key == "" {
+		key = os.Getenv("TOTP_ENCRYPTION_KEY")
+	}
+	aead, err := NewTOTPSecretAEAD(key)
+	if err != nil {
+		log.Fatalf("init totp: %v", err)
+	}
+	totpSecretAEAD = aead
+}
+
+// NewTOTPSecretAEAD 根据 base64 编码的 32 字节密钥创建 AES-256-GCM 加密器
+func NewTOTPSecretAEAD(key string) (cipher.AEAD, error) {
+	if key == "" {
+		return nil, errors.New("totp encryption key not configured")
+	}
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("decode totp encryption key: %w", err)
+	}
+	if len(raw) != 32 {
+		return nil, fmt.Errorf("totp encryption key must be 32 bytes, got %d", len(raw))
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptTOTPSecret 加密两步验证密钥，userID 作为附加数据，密文复制到其他用户时无法解密
+func EncryptTOTPSecret(userID, secret string) (string, error) {
+	if totpSecretAEAD == nil {
+		return "", errors.New("totp encryption key not configured")
+	}
+	nonce := make([]byte, totpSecretAEAD.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := totpSecretAEAD.Seal(nonce, nonce, []byte(secret), []byte(userID))
+	return encryptedTOTPPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptTOTPSecret 解密数据库中保存的两步验证密钥，加密前保存的明文密钥原样返回
+func DecryptTOTPSecret(userID, stored string) (string, error) {
+	if !IsEncryptedTOTPSecret(stored) {
+		return stored, nil
+	}
+	if totpSecretAEAD == nil {
+		return "", errors.New("totp encryption key not configured")
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(stored, encryptedTOTPPrefix))
+	if err != nil {
+		return "", err
+	}
+	nonceSize := totpSecretAEAD.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", errors.New("invalid encrypted totp secret")
+	}
+	secret, err := totpSecretAEAD.Open(nil, sealed[:nonceSize], sealed[nonceSize:], []byte(userID))
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
+
+// IsEncryptedTOTPSecret 判断数据库中保存的两步验证密钥是否已加密
+func IsEncryptedTOTPSecret(stored string) bool {
+	return strings.HasPrefix(stored, encryptedTOTPPrefix)
+}
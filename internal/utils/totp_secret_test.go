@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"crypto/cipher"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useTOTPSecretKey 测试中使用固定的加密密钥
+func useTOTPSecretKey(t *testing.T, key string) {
+	t.Helper()
+	aead, err := NewTOTPSecretAEAD(key)
+	require.NoError(t, err)
+	prev := totpSecretAEAD
+	totpSecretAEAD = aead
+	t.Cleanup(func() { totpSecretAEAD = prev })
+}
+
+func TestEncryptTOTPSecret(t *testing.T) {
+	useTOTPSecretKey(t, base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32))))
+	secret, err := GenerateTOTPSecret()
+	require.NoError(t, err)
+
+	encrypted, err := EncryptTOTPSecret("user-1", secret)
+	require.NoError(t, err)
+	assert.True(t, IsEncryptedTOTPSecret(encrypted))
+	assert.NotContains(t, encrypted, secret)
+	// 密文长度不能超过 users.totp_secret 列的长度
+	assert.LessOrEqual(t, len(encrypted), 255)
+
+	decrypted, err := DecryptTOTPSecret("user-1", encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, secret, decrypted)
+
+	// 每次加密使用不同的 nonce
+	again, err := EncryptTOTPSecret("user-1", secret)
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, again)
+
+	// 复制到其他用户或被篡改时无法解密
+	_, err = DecryptTOTPSecret("user-2", encrypted)
+	assert.Error(t, err)
+	_, err = DecryptTOTPSecret("user-1", encrypted[:len(encrypted)-2]+"AA")
+	assert.Error(t, err)
+
+	// 加密前保存的明文密钥原样返回
+	decrypted, err = DecryptTOTPSecret("user-1", secret)
+	require.NoError(t, err)
+	assert.Equal(t, secret, decrypted)
+
+	// 更换密钥后旧密文无法解密
+	useTOTPSecretKey(t, base64.StdEncoding.EncodeToString([]byte(strings.Repeat("x", 32))))
+	_, err = DecryptTOTPSecret("user-1", encrypted)
+	assert.Error(t, err)
+}
+
+func TestNewTOTPSecretAEAD(t *testing.T) {
+	for _, key := range []string{"", "not-base64!", base64.StdEncoding.EncodeToString([]byte("short"))} {
+		_, err := NewTOTPSecretAEAD(key)
+		assert.Error(t, err, key)
+	}
+
+	var prev cipher.AEAD
+	prev, totpSecretAEAD = totpSecretAEAD, nil
+	defer func() { totpSecretAEAD = prev }()
+	_, err := EncryptTOTPSecret("user-1", "SECRET")
+	assert.Error(t, err)
+}
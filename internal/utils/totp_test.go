@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 附录 B 的 SHA1 测试向量，取后 6 位
+func TestTOTPCodeRFC6238(t *testing.T) {
+	secret := totpEncoding.EncodeToString([]byte("12345678901234567890"))
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		got, err := TOTPCode(secret, TOTPStep(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("TOTPCode at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	code, err := TOTPCode(secret, TOTPStep(now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	step, ok := VerifyTOTP(secret, code, now)
+	if !ok || step != TOTPStep(now) {
+		t.Errorf("VerifyTOTP current code = (%d, %v)", step, ok)
+	}
+	// 允许一个时间步的时钟偏差
+	if _, ok := VerifyTOTP(secret, code, now.Add(TOTPPeriod*time.Second)); !ok {
+		t.Error("code from previous step should be accepted")
+	}
+	if _, ok := VerifyTOTP(secret, code, now.Add(3*TOTPPeriod*time.Second)); ok {
+		t.Error("code from three steps ago should be rejected")
+	}
+	if _, ok := VerifyTOTP(secret, "12345", now); ok {
+		t.Error("short code should be rejected")
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := TOTPURI("taxin", "alice", "JBSWY3DPEHPK3PXP")
+	u, err := url.Parse(uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" || !strings.HasSuffix(u.Path, "taxin:alice") {
+		t.Errorf("unexpected uri %s", uri)
+	}
+	if u.Query().Get("secret") != "JBSWY3DPEHPK3PXP" || u.Query().Get("issuer") != "taxin" {
+		t.Errorf("unexpected query %s", u.RawQuery)
+	}
+}
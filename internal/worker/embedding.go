@@ -0,0 +1,203 @@
+package worker
+
+// 异步生成用户喜好向量的后台任务
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/HCH1212/taxin/config"
+	"github.com/HCH1212/taxin/internal/dao"
+	"github.com/HCH1212/taxin/internal/model"
+	"github.com/HCH1212/taxin/internal/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+// 未配置时的默认值
+const (
+	defaultPollInterval = time.Second
+	defaultBatchSize    = 10
+	defaultMaxAttempts  = 5
+	defaultRetryBackoff = 5 * time.Second
+	defaultLeaseTimeout = time.Minute
+	maxRetryBackoff     = time.Hour
+)
+
+// EmbeddingWorker 轮询 embedding_jobs 表，为注册时未生成向量的用户补全喜好向量
+type EmbeddingWorker struct {
+	pollInterval time.Duration
+	batchSize    int
+	maxAttempts  int
+	retryBackoff time.Duration
+	leaseTimeout time.Duration
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewEmbeddingWorker 根据配置创建后台任务
+func NewEmbeddingWorker(conf config.EmbeddingWorker) *EmbeddingWorker {
+	w := &EmbeddingWorker{
+		pollInterval: conf.PollInterval,
+		batchSize:    conf.BatchSize,
+		maxAttempts:  conf.MaxAttempts,
+		retryBackoff: conf.RetryBackoff,
+		leaseTimeout: conf.LeaseTimeout,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	if w.pollInterval <= 0 {
+		w.pollInterval = defaultPollInterval
+	}
+	if w.batchSize <= 0 {
+		w.batchSize = defaultBatchSize
+	}
+	if w.maxAttempts <= 0 {
+		w.maxAttempts = defaultMaxAttempts
+	}
+	if w.retryBackoff <= 0 {
+		w.retryBackoff = defaultRetryBackoff
+	}
+	if w.leaseTimeout <= 0 {
+		w.leaseTimeout = defaultLeaseTimeout
+	}
+	return w
+}
+
+// Start 在后台开始轮询任务
+func (w *EmbeddingWorker) Start() {
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(w.pollInterval)
+		defer ticker.Stop()
+		for {
+			// 一批处理满时说明还有积压，立即继续处理
+			for {
+				n, err := w.RunOnce(context.Background())
+				if err != nil {
+					log.Printf("embedding worker: %v", err)
+				}
+				if n < w.batchSize {
+					break
+				}
+				select {
+				case <-w.stop:
+					return
+				default:
+				}
+			}
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop 停止轮询，等待正在处理的任务结束
+func (w *EmbeddingWorker) Stop(ctx context.Context) error {
+	close(w.stop)
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RunOnce 领取并处理一批任务，返回领取到的任务数
+func (w *EmbeddingWorker) RunOnce(ctx context.Context) (int, error) {
+	jobs, err := model.ClaimEmbeddingJobs(dao.DB, w.batchSize, w.leaseTimeout)
+	if err != nil {
+		return 0, err
+	}
+	for _, job := range jobs {
+		w.process(ctx, job)
+	}
+	return len(jobs), nil
+}
+
+// process 处理单个任务，失败时按指数退避重试，超过最大次数后进入死信状态
+func (w *EmbeddingWorker) process(ctx context.Context, job model.EmbeddingJob) {
+	tr := otel.Tracer("embedding-worker")
+	ctx, span := tr.Start(ctx, "ProcessEmbeddingJob")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("user_id", job.UserID),
+		attribute.Int("attempts", job.Attempts),
+	)
+
+	embedErr := EmbedUser(ctx, dao.DB, job.UserID)
+	if embedErr == nil {
+		if err := model.CompleteEmbeddingJob(dao.DB, job.ID); err != nil {
+			span.SetStatus(codes.Error, "complete job failed")
+			log.Printf("embedding worker: complete job %d: %v", job.ID, err)
+		}
+		return
+	}
+	span.RecordError(embedErr)
+
+	// 用户已被删除，任务无需重试
+	if embedErr == gorm.ErrRecordNotFound {
+		span.SetStatus(codes.Error, "user not found")
+		if err := model.DeadEmbeddingJob(dao.DB, job.ID, "user not found"); err != nil {
+			log.Printf("embedding worker: dead job %d: %v", job.ID, err)
+		}
+		return
+	}
+
+	if job.Attempts >= w.maxAttempts {
+		span.SetStatus(codes.Error, "embedding job dead")
+		log.Printf("embedding worker: job %d for user %s failed %d times, giving up: %v", job.ID, job.UserID, job.Attempts, embedErr)
+		err := dao.DB.Transaction(func(tx *gorm.DB) error {
+			if err := model.DeadEmbeddingJob(tx, job.ID, embedErr.Error()); err != nil {
+				return err
+			}
+			return model.UpdateUserEmbeddingStatus(tx, job.UserID, model.EmbeddingStatusFailed)
+		})
+		if err != nil {
+			log.Printf("embedding worker: dead job %d: %v", job.ID, err)
+		}
+		return
+	}
+
+	span.SetStatus(codes.Error, "embedding job failed, will retry")
+	backoff := w.retryBackoff << (job.Attempts - 1)
+	if backoff > maxRetryBackoff || backoff <= 0 {
+		backoff = maxRetryBackoff
+	}
+	if err := model.RetryEmbeddingJob(dao.DB, job.ID, time.Now().Add(backoff), embedErr.Error()); err != nil {
+		log.Printf("embedding worker: retry job %d: %v", job.ID, err)
+	}
+}
+
+// EmbedUser 为用户当前的喜好重新生成画像向量和每个喜好的向量，并标记为已生成
+func EmbedUser(ctx context.Context, db *gorm.DB, userID string) error {
+	user, err := model.GetUserByUserID(db, userID)
+	if err != nil {
+		return err
+	}
+	likes := user.GetLikeList()
+	likeEmbeddings, err := utils.GenerateLikeEmbeddings(ctx, likes)
+	if err != nil {
+		return err
+	}
+	embedding, err := utils.ProfileEmbedding(likeEmbeddings)
+	if err != nil {
+		return err
+	}
+
+	embeddingModel := utils.EmbeddingModel()
+	userLikes := model.NewUserLikes(userID, likes, likeEmbeddings, embeddingModel)
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := model.UpdateUserEmbedding(tx, userID, &embedding, embeddingModel, model.EmbeddingStatusReady); err != nil {
+			return err
+		}
+		return model.ReplaceUserLikes(tx, userID, userLikes)
+	})
+}
@@ -0,0 +1,133 @@
+package worker
+
+// 彻底删除超过宽限期的已注销账号
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/HCH1212/taxin/config"
+	"github.com/HCH1212/taxin/internal/dao"
+	"github.com/HCH1212/taxin/internal/model"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+// 未配置时的默认值
+const (
+	defaultPurgeInterval  = time.Hour
+	defaultPurgeBatchSize = 100
+)
+
+// PurgeWorker 定期彻底删除注销超过宽限期的用户
+type PurgeWorker struct {
+	gracePeriod time.Duration
+	interval    time.Duration
+	batchSize   int
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewPurgeWorker 根据配置创建后台任务
+func NewPurgeWorker(conf config.Account) *PurgeWorker {
+	w := &PurgeWorker{
+		gracePeriod: conf.DeleteGracePeriod,
+		interval:    conf.PurgeInterval,
+		batchSize:   conf.PurgeBatchSize,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	if w.gracePeriod <= 0 {
+		w.gracePeriod = model.DefaultDeleteGracePeriod
+	}
+	if w.interval <= 0 {
+		w.interval = defaultPurgeInterval
+	}
+	if w.batchSize <= 0 {
+		w.batchSize = defaultPurgeBatchSize
+	}
+	return w
+}
+
+// Start 在后台定期执行清理
+func (w *PurgeWorker) Start() {
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			// 本批有用户被删除时说明可能还有积压，立即继续处理
+			// 没有删除任何用户（没有积压或全部失败）时等到下一次定时，避免反复处理同一批失败的用户
+			for {
+				n, err := w.RunOnce(context.Background())
+				if err != nil {
+					log.Printf("purge worker: %v", err)
+				}
+				if n == 0 {
+					break
+				}
+				select {
+				case <-w.stop:
+					return
+				default:
+				}
+			}
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop 停止清理，等待正在处理的批次结束
+func (w *PurgeWorker) Stop(ctx context.Context) error {
+	close(w.stop)
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RunOnce 清理一批超过宽限期的用户，返回实际删除的用户数
+func (w *PurgeWorker) RunOnce(ctx context.Context) (int, error) {
+	userIDs, err := model.ListUsersToPurge(dao.DB, time.Now().Add(-w.gracePeriod), w.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, userID := range userIDs {
+		if w.purge(ctx, userID) {
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// purge 彻底删除单个用户，返回是否删除成功
+func (w *PurgeWorker) purge(ctx context.Context, userID string) bool {
+	tr := otel.Tracer("purge-worker")
+	_, span := tr.Start(ctx, "PurgeUser")
+	defer span.End()
+	span.SetAttributes(attribute.String("user_id", userID))
+
+	err := model.PurgeUser(dao.DB, userID)
+	// 已被恢复或已被其他实例删除
+	if err == gorm.ErrRecordNotFound {
+		return false
+	}
+	if err != nil {
+		span.SetStatus(codes.Error, "purge user failed")
+		log.Printf("purge worker: purge user %s: %v", userID, err)
+		return false
+	}
+	span.AddEvent("user purged")
+	return true
+}
@@ -0,0 +1,154 @@
+package worker
+
+// 更换词嵌入模型后，为已有用户重新生成向量的回填任务
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/HCH1212/taxin/internal/dao"
+	"github.com/HCH1212/taxin/internal/model"
+	"github.com/HCH1212/taxin/internal/utils"
+	"github.com/go-redis/redis/v8"
+)
+
+// Reembedder 按主键顺序分批为向量模型或维度与当前配置不一致的用户重新生成向量
+// 每个用户单独提交，可以在服务运行期间执行；进度保存在 Redis 中，中断后再次运行会从上次的位置继续
+type Reembedder struct {
+	BatchSize int     // 每批查询的用户数
+	Rate      float64 // 每秒最多处理的用户数，<= 0 表示不限速
+	Reset     bool    // 忽略已保存的进度，从头开始
+}
+
+// ReembedProgress 回填进度
+type ReembedProgress struct {
+	Total     int64 // 开始时需要处理的用户数
+	Processed int64 // 已处理的用户数，包括失败的
+	Failed    int64 // 失败的用户数，下次运行时会重新处理
+	LastID    uint  // 最后处理的用户主键
+}
+
+// reembedCursorKey 保存回填进度的 Redis 键，按目标模型和维度区分
+func reembedCursorKey(embeddingModel string, dim int) string {
+	return "reembed:cursor:" + embeddingModel + ":" + strconv.Itoa(dim)
+}
+
+// Run 执行回填，ctx 取消时处理完当前用户后退出并保存进度
+func (r *Reembedder) Run(ctx context.Context) (ReembedProgress, error) {
+	var progress ReembedProgress
+	embeddingModel := utils.EmbeddingModel()
+	dim := utils.EmbeddingDimension
+	cursorKey := reembedCursorKey(embeddingModel, dim)
+	batchSize := r.BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	// 读取上次的进度
+	if r.Reset {
+		if err := dao.RedisClient.Del(ctx, cursorKey).Err(); err != nil {
+			return progress, err
+		}
+	} else {
+		lastID, err := dao.RedisClient.Get(ctx, cursorKey).Uint64()
+		if err != nil && err != redis.Nil {
+			return progress, err
+		}
+		progress.LastID = uint(lastID)
+	}
+
+	total, err := model.CountUsersToReembed(dao.DB, embeddingModel, dim)
+	if err != nil {
+		return progress, err
+	}
+	progress.Total = total
+	log.Printf("reembed: %d users to re-embed with model %s (dim %d), starting after id %d", total, embeddingModel, dim, progress.LastID)
+
+	// 限速
+	var throttle <-chan time.Time
+	if r.Rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / r.Rate))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	start := time.Now()
+	for {
+		users, err := model.ListUsersToReembed(dao.DB, embeddingModel, dim, progress.LastID, batchSize)
+		if err != nil {
+			return progress, err
+		}
+		if len(users) == 0 {
+			break
+		}
+		// 每批结束或中断时保存进度
+		err = processReembedBatch(ctx, users, &progress, throttle, embedUser, func(ctx context.Context, lastID uint) error {
+			return dao.RedisClient.Set(ctx, cursorKey, lastID, 0).Err()
+		})
+		if err != nil {
+			return progress, err
+		}
+		logReembedProgress(progress, start)
+	}
+
+	// 全部完成后清除进度，失败的用户下次运行时从头重新处理
+	if err := dao.RedisClient.Del(ctx, cursorKey).Err(); err != nil {
+		return progress, err
+	}
+	return progress, nil
+}
+
+// embedUser 为单个用户重新生成向量
+func embedUser(ctx context.Context, userID string) error {
+	return EmbedUser(ctx, dao.DB, userID)
+}
+
+// processReembedBatch 依次处理一批用户并更新进度，ctx 取消时处理完当前用户后停止
+// 无论正常结束还是被取消都会保存进度，ctx 已取消时使用不会被取消的 ctx 保存
+func processReembedBatch(ctx context.Context, users []model.User, progress *ReembedProgress, throttle <-chan time.Time,
+	embed func(ctx context.Context, userID string) error, saveCursor func(ctx context.Context, lastID uint) error) error {
+	var err error
+	for _, user := range users {
+		if throttle != nil {
+			select {
+			case <-ctx.Done():
+			case <-throttle:
+			}
+		}
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		if embedErr := embed(ctx, user.UserID); embedErr != nil {
+			// 因取消而失败的用户不计入进度，下次运行时重新处理
+			if err = ctx.Err(); err != nil {
+				break
+			}
+			progress.Failed++
+			log.Printf("reembed: user %s failed: %v", user.UserID, embedErr)
+		}
+		progress.Processed++
+		progress.LastID = user.ID
+	}
+	if saveErr := saveCursor(context.WithoutCancel(ctx), progress.LastID); saveErr != nil {
+		if err != nil {
+			log.Printf("reembed: save progress: %v", saveErr)
+			return err
+		}
+		return saveErr
+	}
+	return err
+}
+
+// logReembedProgress 输出进度、速度和预计剩余时间
+func logReembedProgress(progress ReembedProgress, start time.Time) {
+	elapsed := time.Since(start)
+	rate := float64(progress.Processed) / elapsed.Seconds()
+	var eta time.Duration
+	if rate > 0 && progress.Total > progress.Processed {
+		eta = time.Duration(float64(progress.Total-progress.Processed) / rate * float64(time.Second))
+	}
+	log.Printf("reembed: %d/%d processed, %d failed, %.1f users/s, eta %s",
+		progress.Processed, progress.Total, progress.Failed, rate, eta.Round(time.Second))
+}
@@ -0,0 +1,91 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HCH1212/taxin/internal/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func reembedTestUsers() []model.User {
+	users := make([]model.User, 5)
+	for i := range users {
+		users[i] = model.User{Model: gorm.Model{ID: uint(i + 1)}, UserID: string(rune('a' + i))}
+	}
+	return users
+}
+
+func TestProcessReembedBatch(t *testing.T) {
+	users := reembedTestUsers()
+	var progress ReembedProgress
+	var saved []uint
+	err := processReembedBatch(context.Background(), users, &progress, nil,
+		func(ctx context.Context, userID string) error {
+			if userID == "b" {
+				return errors.New("embed failed")
+			}
+			return nil
+		},
+		func(ctx context.Context, lastID uint) error {
+			saved = append(saved, lastID)
+			return nil
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), progress.Processed)
+	assert.Equal(t, int64(1), progress.Failed)
+	assert.Equal(t, uint(5), progress.LastID)
+	assert.Equal(t, []uint{5}, saved)
+}
+
+func TestProcessReembedBatchCancel(t *testing.T) {
+	users := reembedTestUsers()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var progress ReembedProgress
+	var saved []uint
+	err := processReembedBatch(ctx, users, &progress, nil,
+		func(ctx context.Context, userID string) error {
+			// 处理第三个用户时被取消
+			if userID == "c" {
+				cancel()
+				return ctx.Err()
+			}
+			return nil
+		},
+		func(ctx context.Context, lastID uint) error {
+			// 保存进度时使用的 ctx 不会被取消
+			assert.NoError(t, ctx.Err())
+			saved = append(saved, lastID)
+			return nil
+		})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int64(2), progress.Processed)
+	assert.Equal(t, int64(0), progress.Failed)
+	assert.Equal(t, uint(2), progress.LastID)
+	assert.Equal(t, []uint{2}, saved)
+}
+
+func TestProcessReembedBatchCancelWhileThrottled(t *testing.T) {
+	users := reembedTestUsers()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var progress ReembedProgress
+	var saved []uint
+	// 限速通道不会有数据，只能因取消退出
+	err := processReembedBatch(ctx, users, &progress, make(chan time.Time),
+		func(ctx context.Context, userID string) error {
+			t.Fatalf("unexpected embed for user %s", userID)
+			return nil
+		},
+		func(ctx context.Context, lastID uint) error {
+			saved = append(saved, lastID)
+			return nil
+		})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int64(0), progress.Processed)
+	assert.Equal(t, []uint{0}, saved)
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"os"
+	"time"
 
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/otel"
@@ -12,6 +14,7 @@ import (
 	"go.opentelemetry.io/otel/codes"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	pb_system "github.com/HCH1212/taxin/api/pb/system"
 	pb_user "github.com/HCH1212/taxin/api/pb/user"
@@ -61,9 +64,9 @@ func testUserService(ctx context.Context, conn *grpc.ClientConn) {
 	}
 	fmt.Printf("Register User ID: %s\n", registerResp.UserId)
 
-	// 测试登录
+	// 测试使用用户名登录
 	loginReq := &pb_user.LoginReq{
-		UserId:   registerResp.UserId,
+		Username: registerReq.Username,
 		Password: "testpassword",
 	}
 	loginResp, err := client.Login(ctx, loginReq)
@@ -88,6 +91,45 @@ func testUserService(ctx context.Context, conn *grpc.ClientConn) {
 	// fmt.Printf("Update At: %s\n", userInfoResp.UpdateAt)
 	// fmt.Printf("Username %s\n", userInfoResp.Username)
 
+	// 测试修改喜好
+	updateResp, err := client.UpdateProfile(ctxWithToken, &pb_user.UpdateProfileReq{
+		Like:       []string{"reading", "swimming", "hiking"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"like"}},
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to update profile")
+		log.Fatalf("Failed to update profile: %v", err)
+	}
+	fmt.Printf("Updated Likes: %v, embedding status: %s\n", updateResp.Like, updateResp.EmbeddingStatus)
+
+	// 异步生成向量时等待生成完成
+	for i := 0; i < 10 && updateResp.EmbeddingStatus == "pending"; i++ {
+		time.Sleep(time.Second)
+		if updateResp, err = client.GetUserInfo(ctxWithToken, userInfoReq); err != nil {
+			log.Fatalf("Failed to get user info: %v", err)
+		}
+	}
+
+	// 测试查找兴趣相近的用户
+	similarResp, err := client.FindSimilarUsers(ctxWithToken, &pb_user.FindSimilarUsersReq{Limit: 5})
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to find similar users")
+		log.Fatalf("Failed to find similar users: %v", err)
+	}
+	for _, similarUser := range similarResp.Users {
+		fmt.Printf("Similar User: %s (%s), distance: %.4f\n", similarUser.Username, similarUser.UserId, similarUser.Distance)
+	}
+
+	// 测试查看登录设备
+	sessionsResp, err := client.ListSessions(ctxWithToken, &pb_user.ListSessionsReq{})
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to list sessions")
+		log.Fatalf("Failed to list sessions: %v", err)
+	}
+	for _, session := range sessionsResp.Sessions {
+		fmt.Printf("Session: %s %s (%s), last seen: %s, current: %v\n", session.SessionId, session.Ip, session.UserAgent, session.LastSeenAt, session.Current)
+	}
+
 	// 添加自定义标签和事件
 	span.SetAttributes(attribute.String("user_id", registerResp.UserId))
 	span.AddEvent("User service tests completed successfully")
@@ -98,6 +140,14 @@ func testSystemService(ctx context.Context, conn *grpc.ClientConn) {
 	ctx, span := tr.Start(ctx, "TestSystemService")
 	defer span.End()
 
+	// SendFile 只对管理员开放，需要通过 ADMIN_TOKEN 提供管理员的 access_token
+	adminToken := os.Getenv("ADMIN_TOKEN")
+	if adminToken == "" {
+		log.Println("ADMIN_TOKEN not set, skipping SendFile test")
+		return
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+adminToken)
+
 	// 创建 SystemService 客户端
 	client := pb_system.NewSystemServiceClient(conn)
 
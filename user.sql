@@ -21,6 +21,12 @@ CREATE TABLE users (
     password VARCHAR(255) NOT NULL,
     "like" TEXT NOT NULL,
     like_embedding vector (768), -- 使用小写vector类型
+    embedding_status VARCHAR(16) NOT NULL DEFAULT 'ready', -- pending、ready、failed
+    embedding_model VARCHAR(255), -- 生成向量所用的模型
+    embedding_dim INT, -- 向量维度
+    role VARCHAR(16) NOT NULL DEFAULT 'user', -- user、support、admin
+    disabled_at TIMESTAMP, -- 被管理员禁用的时间
+    totp_secret VARCHAR(255) NOT NULL DEFAULT '', -- 加密后的两步验证密钥，为空表示未开启
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     deleted_at TIMESTAMP,
@@ -50,4 +56,85 @@ CREATE INDEX idx_users_user_id ON users (user_id);
 CREATE INDEX idx_users_like_embedding ON users USING ivfflat (
     like_embedding vector_cosine_ops
 )
-WITH (lists = 100);
\ No newline at end of file
+WITH (lists = 100);
+
+-- 8. 创建用户喜好表，每个喜好单独存储词嵌入向量，用于按单个兴趣匹配用户
+CREATE TABLE user_likes (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL REFERENCES users (user_id),
+    "like" TEXT NOT NULL,
+    embedding vector (768),
+    embedding_model VARCHAR(255), -- 生成向量所用的模型
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE TRIGGER update_user_likes_timestamp
+BEFORE UPDATE ON user_likes
+FOR EACH ROW
+EXECUTE FUNCTION update_timestamp();
+
+CREATE INDEX idx_user_likes_user_id ON user_likes (user_id);
+
+CREATE INDEX idx_user_likes_embedding ON user_likes USING ivfflat (
+    embedding vector_cosine_ops
+)
+WITH (lists = 100);
+
+-- 9. 创建异步词嵌入任务表
+CREATE TABLE embedding_jobs (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending', -- pending、done、dead
+    attempts INT NOT NULL DEFAULT 0,
+    next_run_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE TRIGGER update_embedding_jobs_timestamp
+BEFORE UPDATE ON embedding_jobs
+FOR EACH ROW
+EXECUTE FUNCTION update_timestamp();
+
+CREATE INDEX idx_embedding_jobs_pending ON embedding_jobs (next_run_at)
+WHERE status = 'pending';
+
+CREATE INDEX idx_embedding_jobs_user_id ON embedding_jobs (user_id);
+
+-- 10. 创建两步验证恢复码表
+CREATE TABLE totp_recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL REFERENCES users (user_id),
+    code_hash VARCHAR(255) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE TRIGGER update_totp_recovery_codes_timestamp
+BEFORE UPDATE ON totp_recovery_codes
+FOR EACH ROW
+EXECUTE FUNCTION update_timestamp();
+
+CREATE INDEX idx_totp_recovery_codes_user_id ON totp_recovery_codes (user_id);
+
+-- 11. 创建审计日志表
+CREATE TABLE audit_events (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(255), -- 无法确定用户时为空
+    event VARCHAR(64) NOT NULL, -- login_locked、login_rate_limited 等
+    ip VARCHAR(64),
+    detail JSONB,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX idx_audit_events_user_id ON audit_events (user_id, id);
+
+CREATE INDEX idx_audit_events_event ON audit_events (event, created_at);